@@ -16,15 +16,26 @@ package entadapter
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
-	"reflect"
+	"io"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	atlas "ariga.io/atlas/sql/schema"
 	"entgo.io/ent/dialect"
 	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/schema"
 	"github.com/casbin/casbin/v3/persist"
 	"github.com/casbin/ent-adapter/ent/casbinrule"
+	"github.com/casbin/ent-adapter/ent/migrate"
 	"github.com/casbin/ent-adapter/ent/predicate"
 
 	"github.com/casbin/casbin/v3/model"
@@ -33,6 +44,8 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	_ "modernc.org/sqlite"
 )
 
 const (
@@ -40,37 +53,154 @@ const (
 	DefaultDatabase  = "casbin"
 )
 
+// legacyTableName is the table older versions stored the rules in.
+const legacyTableName = "casbin_rules"
+
 type Adapter struct {
 	client *ent.Client
 	ctx    context.Context
+	// db is the database of the client, if it could be determined.
+	db *sql.DB
+	// replica is the client used for loading the policy, if any.
+	replica *ent.Client
+	// replicaDB is the database the replica client is built on, if given.
+	replicaDB *sql.DB
+	// lastWrite is the time of the last committed write in Unix nanoseconds.
+	lastWrite atomic.Int64
+	// txs holds the transactions the adapter began that are still open.
+	txs sync.Map
+	// owned reports whether the adapter opened db itself and closes it.
+	owned bool
+	// pool holds the connection pool settings to apply to db.
+	pool []func(*sql.DB)
+
+	tableName      string
+	tablePrefix    string
+	onConflict     OnConflict
+	autoMigrate    bool
+	batchSize      int
+	logger         func(...any)
+	readOnly       bool
+	isolation      sql.IsolationLevel
+	saveMode       SaveMode
+	bulkStrategy   BulkStrategy
+	pageSize       int
+	parallelism    int
+	readYourWrites time.Duration
+	retry          *RetryPolicy
+	snapshotLoad   bool
 
 	filtered bool
 }
 
-type Filter struct {
-	Ptype []string
-	V0    []string
-	V1    []string
-	V2    []string
-	V3    []string
-	V4    []string
-	V5    []string
+var (
+	// ErrPolicyExists is returned when a rule being added is already stored
+	// and the adapter uses OnConflictError.
+	ErrPolicyExists = errors.New("policy already exists")
+	// ErrReadOnly is returned by every write of an adapter created WithReadOnly.
+	ErrReadOnly = errors.New("adapter is read-only")
+	// ErrTooManyFields is returned for rules with more than MaxFields fields,
+	// which cannot be stored without losing data.
+	ErrTooManyFields = errors.New("too many rule fields")
+	// ErrLegacyTable is returned by Migrate if the default table does not
+	// exist yet but the table of older versions, casbin_rules, does. Creating
+	// the default table would silently start from an empty policy.
+	ErrLegacyTable = errors.New(`the rules of older versions are stored in "casbin_rules", use WithTableName("casbin_rules") to keep using them`)
+)
+
+// MaxFields is the number of rule fields that can be stored, in the columns V0 to V9.
+const MaxFields = 10
+
+// fieldColumns holds the columns of the rule fields in order.
+var fieldColumns = []string{
+	casbinrule.FieldV0, casbinrule.FieldV1, casbinrule.FieldV2, casbinrule.FieldV3, casbinrule.FieldV4,
+	casbinrule.FieldV5, casbinrule.FieldV6, casbinrule.FieldV7, casbinrule.FieldV8, casbinrule.FieldV9,
 }
 
-type Option func(a *Adapter) error
+// ruleColumns holds the columns that identify a rule, its ptype and its fields.
+var ruleColumns = append([]string{casbinrule.FieldPtype}, fieldColumns...)
+
+// insertColumns is the number of columns set for each inserted rule,
+// its ptype, its fields, its arity and its hash.
+var insertColumns = len(ruleColumns) + 2
+
+// conflictColumns is the conflict target of the unique index on the rule hash.
+var conflictColumns = entsql.ConflictColumns(casbinrule.FieldRuleHash)
+
+var (
+	_ persist.ContextAdapter          = (*Adapter)(nil)
+	_ persist.ContextBatchAdapter     = (*Adapter)(nil)
+	_ persist.ContextUpdatableAdapter = (*Adapter)(nil)
+	_ persist.ContextFilteredAdapter  = (*Adapter)(nil)
+)
 
 func open(driverName, dataSourceName string) (*ent.Client, error) {
+	if isSQLite(driverName) {
+		driverName, dataSourceName = sqliteSource(driverName, dataSourceName)
+	}
 	db, err := sql.Open(driverName, dataSourceName)
 	if err != nil {
 		return nil, err
 	}
-	var drv dialect.Driver
-	if driverName == "pgx" {
-		drv = entsql.OpenDB(dialect.Postgres, db)
-	} else {
-		drv = entsql.OpenDB(driverName, db)
+	if isSQLite(driverName) && isSQLiteMemory(dataSourceName) {
+		// Every connection to an in-memory database opens a new, empty one.
+		db.SetMaxOpenConns(1)
+	}
+	return ent.NewClient(ent.Driver(openDB(driverName, db))), nil
+}
+
+// openDB returns a driver for db, which was opened with driverName.
+// The pgx driver is mapped to the Postgres dialect and the sqlite driver
+// to the SQLite dialect.
+func openDB(driverName string, db *sql.DB) *entsql.Driver {
+	switch driverName {
+	case "pgx":
+		return entsql.OpenDB(dialect.Postgres, db)
+	case "sqlite":
+		return entsql.OpenDB(dialect.SQLite, db)
+	}
+	return entsql.OpenDB(driverName, db)
+}
+
+// isSQLite reports whether driverName is the name of a SQLite driver.
+func isSQLite(driverName string) bool {
+	return driverName == dialect.SQLite || driverName == "sqlite"
+}
+
+// sqliteSource returns the driver name and data source to open a SQLite
+// database with. The pure-Go driver registered as "sqlite" is used unless
+// another driver was registered as "sqlite3", and foreign keys, which the
+// migration requires, are enabled together with a busy timeout.
+func sqliteSource(driverName, dataSourceName string) (string, string) {
+	if driverName == dialect.SQLite && !slices.Contains(sql.Drivers(), dialect.SQLite) {
+		driverName = "sqlite"
+	}
+	if driverName != "sqlite" {
+		return driverName, dataSourceName
+	}
+	pragmas := make([]string, 0, 2)
+	if !strings.Contains(dataSourceName, "foreign_keys") {
+		pragmas = append(pragmas, "_pragma=foreign_keys(1)")
+	}
+	if !strings.Contains(dataSourceName, "busy_timeout") {
+		pragmas = append(pragmas, "_pragma=busy_timeout(5000)")
+	}
+	if len(pragmas) == 0 {
+		return driverName, dataSourceName
+	}
+	if !strings.HasPrefix(dataSourceName, "file:") {
+		dataSourceName = "file:" + dataSourceName
 	}
-	return ent.NewClient(ent.Driver(drv)), nil
+	sep := "?"
+	if strings.Contains(dataSourceName, "?") {
+		sep = "&"
+	}
+	return driverName, dataSourceName + sep + strings.Join(pragmas, "&")
+}
+
+// isSQLiteMemory reports whether dataSourceName refers to an in-memory SQLite database.
+func isSQLiteMemory(dataSourceName string) bool {
+	return strings.Contains(dataSourceName, ":memory:") || strings.Contains(dataSourceName, "mode=memory")
 }
 
 // NewAdapter returns an adapter by driver name and data source string.
@@ -79,16 +209,9 @@ func NewAdapter(driverName, dataSourceName string, options ...Option) (*Adapter,
 	if err != nil {
 		return nil, err
 	}
-	a := &Adapter{
-		client: client,
-		ctx:    context.Background(),
-	}
-	for _, option := range options {
-		if err := option(a); err != nil {
-			return nil, err
-		}
-	}
-	if err := client.Schema.Create(a.ctx); err != nil {
+	a, err := newAdapter(client, true, options...)
+	if err != nil {
+		_ = client.Close()
 		return nil, err
 	}
 	return a, nil
@@ -96,73 +219,453 @@ func NewAdapter(driverName, dataSourceName string, options ...Option) (*Adapter,
 
 // NewAdapterWithClient create an adapter with client passed in.
 // This method does not ensure the existence of database, user should create database manually.
+// If a table name other than the generated one or a logger is configured, the adapter builds
+// its own client on top of the driver of the given client, so hooks registered on it are not used.
 func NewAdapterWithClient(client *ent.Client, options ...Option) (*Adapter, error) {
+	return newAdapter(client, false, options...)
+}
+
+// NewAdapterWithDB creates an adapter on an existing database handle, keeping
+// its pool settings and connector. dialectName is the name of the driver db was
+// opened with, such as "mysql", "postgres", "pgx", "sqlite3" or "sqlite".
+func NewAdapterWithDB(db *sql.DB, dialectName string, options ...Option) (*Adapter, error) {
+	if db == nil {
+		return nil, errors.New("database must not be nil")
+	}
+	switch dialectName {
+	case dialect.MySQL, dialect.Postgres, dialect.SQLite, "pgx", "sqlite":
+	default:
+		return nil, fmt.Errorf("unsupported dialect: %q", dialectName)
+	}
+	return newAdapter(ent.NewClient(ent.Driver(openDB(dialectName, db))), false, options...)
+}
+
+// NewAdapterWithDriver creates an adapter on an existing ent driver,
+// e.g. one wrapped for tracing.
+func NewAdapterWithDriver(drv dialect.Driver, options ...Option) (*Adapter, error) {
+	if drv == nil {
+		return nil, errors.New("driver must not be nil")
+	}
+	if d, ok := drv.(*entsql.Driver); ok && (d.Dialect() == "pgx" || d.Dialect() == "sqlite") {
+		if db, ok := d.ExecQuerier.(*sql.DB); ok {
+			drv = openDB(d.Dialect(), db)
+		}
+	}
+	return newAdapter(ent.NewClient(ent.Driver(drv)), false, options...)
+}
+
+// newAdapter creates an adapter on client. owned reports whether the adapter
+// opened the client itself, in which case it may configure and close it.
+func newAdapter(client *ent.Client, owned bool, options ...Option) (*Adapter, error) {
 	a := &Adapter{
-		client: client,
-		ctx:    context.Background(),
+		ctx:         context.Background(),
+		owned:       owned,
+		tableName:   DefaultTableName,
+		autoMigrate: true,
 	}
 	for _, option := range options {
 		if err := option(a); err != nil {
 			return nil, err
 		}
 	}
-	if err := client.Schema.Create(a.ctx); err != nil {
+	if err := a.validate(); err != nil {
 		return nil, err
 	}
+	if a.batchSize == 0 {
+		a.batchSize = DefaultBatchSize
+	}
+	if a.pageSize == 0 {
+		a.pageSize = DefaultPageSize
+	}
+	a.tableName = a.tablePrefix + a.tableName
+	a.db = sqlDB(client.Driver())
+	for _, configure := range a.pool {
+		configure(a.db)
+	}
+	if err := a.checkBulkStrategy(); err != nil {
+		return nil, err
+	}
+	if a.replicaDB != nil {
+		a.replica = ent.NewClient(ent.Driver(openDB(client.Driver().Dialect(), a.replicaDB)))
+	}
+	if a.tableName != casbinrule.Table || a.logger != nil {
+		client = ent.NewClient(ent.Driver(a.wrapDriver(client.Driver())))
+		if a.replica != nil {
+			a.replica = ent.NewClient(ent.Driver(a.wrapDriver(a.replica.Driver())))
+		}
+	}
+	a.client = client
+	if a.autoMigrate && !a.readOnly {
+		if err := a.Migrate(a.ctx); err != nil {
+			return nil, err
+		}
+	}
 	return a, nil
 }
 
+// wrapDriver wraps drv to use the configured table name and logger.
+func (a *Adapter) wrapDriver(drv dialect.Driver) dialect.Driver {
+	drv = newTableDriver(drv, a.tableName)
+	if a.logger != nil {
+		drv = dialect.Debug(drv, a.logger)
+	}
+	return drv
+}
+
+// tables returns the tables to migrate, renamed to the configured table name.
+func (a *Adapter) tables() []*schema.Table {
+	table := *migrate.CasbinRuleTable
+	table.Name = a.tableName
+	table.Indexes = make([]*schema.Index, 0, len(migrate.CasbinRuleTable.Indexes))
+	for _, index := range migrate.CasbinRuleTable.Indexes {
+		index := *index
+		index.Name = strings.Replace(index.Name, casbinrule.Table, a.tableName, 1)
+		table.Indexes = append(table.Indexes, &index)
+	}
+	return []*schema.Table{&table}
+}
+
+// Migrate creates or updates the policy table and its unique index.
+// It is run on construction unless the adapter is created WithoutAutoMigrate.
+// The options of the ent migrate package, such as migrate.WithDropIndex and
+// migrate.WithDropColumn, are passed to the migration.
+//
+// Rules stored by older versions have no hash yet, so it is set after the
+// table is migrated, and the rules that are stored more than once are removed.
+// It fails with ErrLegacyTable if the default table would be created next to
+// the table of older versions.
+func (a *Adapter) Migrate(ctx context.Context, opts ...schema.MigrateOption) error {
+	if a.readOnly {
+		return ErrReadOnly
+	}
+	if err := a.checkLegacyTable(ctx); err != nil {
+		return err
+	}
+	opts = append([]schema.MigrateOption{a.dropLegacyIndex()}, opts...)
+	if err := migrate.Create(ctx, a.client.Schema, a.tables(), opts...); err != nil {
+		return err
+	}
+	return a.hashRules(ctx)
+}
+
+// MigrateDryRun writes the statements Migrate would execute to w instead of
+// running them. The database is still read to compute the changes.
+func (a *Adapter) MigrateDryRun(ctx context.Context, w io.Writer, opts ...schema.MigrateOption) error {
+	drv := &schema.WriteDriver{Writer: w, Driver: a.client.Driver()}
+	opts = append([]schema.MigrateOption{a.dropLegacyIndex()}, opts...)
+	return migrate.Create(ctx, migrate.NewSchema(drv), a.tables(), opts...)
+}
+
+// checkLegacyTable returns ErrLegacyTable if the adapter uses the default
+// table, which does not exist, while the table of older versions does.
+func (a *Adapter) checkLegacyTable(ctx context.Context) error {
+	if a.tableName != DefaultTableName {
+		return nil
+	}
+	if ok, err := a.tableExists(ctx, DefaultTableName); err != nil || ok {
+		return err
+	}
+	ok, err := a.tableExists(ctx, legacyTableName)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return ErrLegacyTable
+	}
+	return nil
+}
+
+// tableExists reports whether the table name exists in the current database.
+func (a *Adapter) tableExists(ctx context.Context, name string) (bool, error) {
+	var query string
+	switch a.client.Driver().Dialect() {
+	case dialect.MySQL:
+		query = "SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = (SELECT DATABASE()) AND TABLE_NAME = ?"
+	case dialect.Postgres:
+		query = "SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = CURRENT_SCHEMA() AND TABLE_NAME = $1"
+	default:
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	}
+	var rows entsql.Rows
+	if err := a.client.Driver().Query(ctx, query, []any{name}, &rows); err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	n, err := entsql.ScanInt(rows)
+	return n > 0, err
+}
+
+// dropLegacyIndex returns a migrate option that drops the index on the rule
+// columns created by older versions, even without migrate.WithDropIndex.
+// MySQL only indexed a prefix of each column, so it rejected distinct rules,
+// and PostgreSQL rejects rules too long for an index entry.
+func (a *Adapter) dropLegacyIndex() schema.MigrateOption {
+	name := strings.Replace("idx_casbin_rule", casbinrule.Table, a.tableName, 1)
+	return schema.WithDiffHook(func(next schema.Differ) schema.Differ {
+		return schema.DiffFunc(func(current, desired *atlas.Schema) ([]atlas.Change, error) {
+			changes, err := next.Diff(current, desired)
+			if err != nil {
+				return nil, err
+			}
+			table, ok := current.Table(a.tableName)
+			if !ok {
+				return changes, nil
+			}
+			index, ok := table.Index(name)
+			if !ok {
+				return changes, nil
+			}
+			drop := &atlas.DropIndex{I: index}
+			for _, change := range changes {
+				modify, ok := change.(*atlas.ModifyTable)
+				if !ok || modify.T.Name != a.tableName {
+					continue
+				}
+				for _, c := range modify.Changes {
+					if d, ok := c.(*atlas.DropIndex); ok && d.I.Name == name {
+						return changes, nil
+					}
+				}
+				modify.Changes = append(modify.Changes, drop)
+				return changes, nil
+			}
+			return append(changes, &atlas.ModifyTable{T: table, Changes: []atlas.Change{drop}}), nil
+		})
+	})
+}
+
+// hashRules sets the hash of the rules stored without one by older versions.
+// Rules whose hash is stored already are duplicates and are deleted instead,
+// so the row with the lowest id is kept.
+func (a *Adapter) hashRules(ctx context.Context) error {
+	for {
+		lines, err := a.client.CasbinRule.Query().
+			Where(casbinrule.RuleHashIsNil()).
+			Order(ent.Asc(casbinrule.FieldID)).
+			Limit(a.pageSize).
+			All(ctx)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			hash := ruleHash(line.Ptype, CasbinRuleToStringArray(line))
+			err := a.client.CasbinRule.UpdateOneID(line.ID).SetRuleHash(hash).Exec(ctx)
+			if ent.IsConstraintError(err) {
+				err = a.client.CasbinRule.DeleteOneID(line.ID).Exec(ctx)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(lines) < a.pageSize {
+			return nil
+		}
+	}
+}
+
+// Close closes the database connections of the adapter if it opened them
+// itself, i.e. if it was created by NewAdapter. Clients, drivers and databases
+// passed to the other constructors are left open.
+func (a *Adapter) Close() error {
+	if !a.owned {
+		return nil
+	}
+	return a.client.Close()
+}
+
 // LoadPolicy loads all policy rules from the storage.
 func (a *Adapter) LoadPolicy(model model.Model) error {
-	policies, err := a.client.CasbinRule.Query().Order(ent.Asc("id")).All(a.ctx)
+	return a.LoadPolicyCtx(a.ctx, model)
+}
+
+// LoadPolicyCtx loads all policy rules from the storage with context.
+func (a *Adapter) LoadPolicyCtx(ctx context.Context, model model.Model) error {
+	if a.parallelism > 0 {
+		if err := a.loadPolicyParallel(ctx, model); err != nil {
+			return err
+		}
+		a.filtered = false
+		return nil
+	}
+	err := a.loadPages(ctx, a.reader(), nil, func(lines []*ent.CasbinRule) error {
+		for _, line := range lines {
+			if err := loadPolicyLine(line, model); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	for _, policy := range policies {
-		loadPolicyLine(policy, model)
-	}
+	a.filtered = false
 	return nil
 }
 
-// LoadFilteredPolicy loads only policy rules that match the filter.
-// Filter parameter here is a Filter structure
-func (a *Adapter) LoadFilteredPolicy(model model.Model, filter interface{}) error {
+// reader returns the client that loads the policy: the replica, if one is
+// configured and the last write is older than the read-your-writes window,
+// and the primary otherwise.
+func (a *Adapter) reader() *ent.Client {
+	if a.replica == nil {
+		return a.client
+	}
+	if a.readYourWrites > 0 && time.Since(time.Unix(0, a.lastWrite.Load())) < a.readYourWrites {
+		return a.client
+	}
+	return a.replica
+}
 
-	filterValue, ok := filter.(Filter)
-	if !ok {
-		return fmt.Errorf("invalid filter type: %v", reflect.TypeOf(filter))
+// loadPolicyParallel loads the rules of every stored ptype with its own
+// queries, running at most the configured number of them at a time. The rules
+// are added to model by ptype in sorted order, so the result does not depend
+// on which query finishes first.
+func (a *Adapter) loadPolicyParallel(ctx context.Context, model model.Model) error {
+	client := a.reader()
+	ptypes, err := client.CasbinRule.Query().
+		GroupBy(casbinrule.FieldPtype).
+		Strings(ctx)
+	if err != nil {
+		return err
 	}
+	sort.Strings(ptypes)
 
-	session := a.client.CasbinRule.Query()
-	if len(filterValue.Ptype) != 0 {
-		session.Where(casbinrule.PtypeIn(filterValue.Ptype...))
+	results := make([][]*ent.CasbinRule, len(ptypes))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(a.parallelism)
+	for i, ptype := range ptypes {
+		g.Go(func() error {
+			return a.loadPages(gctx, client, []predicate.CasbinRule{casbinrule.PtypeEQ(ptype)}, func(lines []*ent.CasbinRule) error {
+				results[i] = append(results[i], lines...)
+				return nil
+			})
+		})
 	}
-	if len(filterValue.V0) != 0 {
-		session.Where(casbinrule.V0In(filterValue.V0...))
+	if err := g.Wait(); err != nil {
+		return err
 	}
-	if len(filterValue.V1) != 0 {
-		session.Where(casbinrule.V1In(filterValue.V1...))
+
+	for _, lines := range results {
+		for _, line := range lines {
+			if err := loadPolicyLine(line, model); err != nil {
+				return err
+			}
+		}
 	}
-	if len(filterValue.V2) != 0 {
-		session.Where(casbinrule.V2In(filterValue.V2...))
+	return nil
+}
+
+// LoadPolicyPages calls fn with all stored rules, in pages of the configured
+// page size ordered by id. Loading stops at the first error returned by fn.
+func (a *Adapter) LoadPolicyPages(fn func([]*ent.CasbinRule) error) error {
+	return a.LoadPolicyPagesCtx(a.ctx, fn)
+}
+
+// LoadPolicyPagesCtx calls fn with all stored rules with context, in pages of
+// the configured page size ordered by id. Loading stops at the first error returned by fn.
+func (a *Adapter) LoadPolicyPagesCtx(ctx context.Context, fn func([]*ent.CasbinRule) error) error {
+	return a.loadPages(ctx, a.reader(), nil, fn)
+}
+
+// loadPages queries the rules matching cond with client page by page, using the id of
+// the last rule of a page as the start of the next one, and calls fn with
+// each page. Only one page is held in memory at a time. With WithSnapshotLoad,
+// the pages are queried in a read-only transaction, so they all see the same
+// state of the table.
+func (a *Adapter) loadPages(ctx context.Context, client *ent.Client, cond []predicate.CasbinRule, fn func([]*ent.CasbinRule) error) error {
+	if !a.snapshotLoad {
+		return a.queryPages(ctx, client, cond, fn)
 	}
-	if len(filterValue.V3) != 0 {
-		session.Where(casbinrule.V3In(filterValue.V3...))
+	tx, err := client.BeginTx(ctx, snapshotOptions(client.Driver().Dialect()))
+	if err != nil {
+		return err
 	}
-	if len(filterValue.V4) != 0 {
-		session.Where(casbinrule.V4In(filterValue.V4...))
+	if err := a.queryPages(ctx, tx.Client(), cond, fn); err != nil {
+		_ = tx.Rollback()
+		return err
 	}
-	if len(filterValue.V5) != 0 {
-		session.Where(casbinrule.V5In(filterValue.V5...))
+	return tx.Commit()
+}
+
+// snapshotOptions returns the options of a read-only transaction whose
+// queries all see the same snapshot of the database.
+func snapshotOptions(name string) *sql.TxOptions {
+	if name == dialect.SQLite {
+		// SQLite transactions are serializable.
+		return &sql.TxOptions{ReadOnly: true}
 	}
+	// MySQL and PostgreSQL read from one snapshot at REPEATABLE READ.
+	return &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+}
 
-	lines, err := session.All(a.ctx)
+// queryPages implements loadPages.
+func (a *Adapter) queryPages(ctx context.Context, client *ent.Client, cond []predicate.CasbinRule, fn func([]*ent.CasbinRule) error) error {
+	last := 0
+	for {
+		lines, err := client.CasbinRule.Query().
+			Where(cond...).
+			Where(casbinrule.IDGT(last)).
+			Order(ent.Asc(casbinrule.FieldID)).
+			Limit(a.pageSize).
+			All(ctx)
+		if err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		if err := fn(lines); err != nil {
+			return err
+		}
+		if len(lines) < a.pageSize {
+			return nil
+		}
+		last = lines[len(lines)-1].ID
+	}
+}
+
+// LoadFilteredPolicy loads only policy rules that match the filter.
+// Filter parameter here is a Filter, *Filter, []Filter, BatchFilter, FilterExpr or []predicate.CasbinRule
+func (a *Adapter) LoadFilteredPolicy(model model.Model, filter interface{}) error {
+	return a.LoadFilteredPolicyCtx(a.ctx, model, filter)
+}
+
+// LoadFilteredPolicyCtx loads only policy rules that match the filter with context.
+// Filter parameter here is a Filter, *Filter, []Filter, BatchFilter, FilterExpr or []predicate.CasbinRule
+func (a *Adapter) LoadFilteredPolicyCtx(ctx context.Context, model model.Model, filter interface{}) error {
+	return a.loadFilteredPolicy(ctx, model, filter)
+}
+
+// LoadIncrementalFilteredPolicy appends the policy rules that match the filter
+// to the already loaded ones. Rules that are already loaded are skipped.
+func (a *Adapter) LoadIncrementalFilteredPolicy(model model.Model, filter interface{}) error {
+	return a.LoadIncrementalFilteredPolicyCtx(a.ctx, model, filter)
+}
+
+// LoadIncrementalFilteredPolicyCtx appends the policy rules that match the filter
+// to the already loaded ones with context. Rules that are already loaded are skipped.
+func (a *Adapter) LoadIncrementalFilteredPolicyCtx(ctx context.Context, model model.Model, filter interface{}) error {
+	return a.loadFilteredPolicy(ctx, model, filter)
+}
+
+// loadFilteredPolicy adds the policy rules that match the filter to model,
+// which is not cleared, and marks the loaded policy as filtered.
+func (a *Adapter) loadFilteredPolicy(ctx context.Context, model model.Model, filter interface{}) error {
+	cond, err := loadFilterPredicates(filter)
 	if err != nil {
 		return err
 	}
 
-	for _, line := range lines {
-		loadPolicyLine(line, model)
+	err = a.loadPages(ctx, a.reader(), cond, func(lines []*ent.CasbinRule) error {
+		for _, line := range lines {
+			// Rules that are already loaded are skipped by persist.LoadPolicyArray.
+			if err := loadPolicyLine(line, model); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	a.filtered = true
 
@@ -174,68 +677,166 @@ func (a *Adapter) IsFiltered() bool {
 	return a.filtered
 }
 
+// IsFilteredCtx returns true if the loaded policy has been filtered.
+func (a *Adapter) IsFilteredCtx(ctx context.Context) bool {
+	return a.filtered
+}
+
 // SavePolicy saves all policy rules to the storage.
 func (a *Adapter) SavePolicy(model model.Model) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		if _, err := tx.CasbinRule.Delete().Exec(a.ctx); err != nil {
-			return err
+	return a.SavePolicyCtx(a.ctx, model)
+}
+
+// SavePolicyCtx saves all policy rules to the storage with context.
+// Depending on the configured SaveMode, only the changed rules are written
+// or the whole table is rewritten.
+func (a *Adapter) SavePolicyCtx(ctx context.Context, model model.Model) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		if a.saveMode == SaveModeDiff {
+			return a.diffPolicy(ctx, tx, model)
+		}
+		return a.rewritePolicy(ctx, tx, model)
+	})
+}
+
+// rewritePolicy deletes all stored rules and inserts the rules of model.
+func (a *Adapter) rewritePolicy(ctx context.Context, tx *ent.Tx, model model.Model) error {
+	if _, err := tx.CasbinRule.Delete().Exec(ctx); err != nil {
+		return err
+	}
+	lines := make([]*ent.CasbinRuleCreate, 0)
+
+	for ptype, ast := range model["p"] {
+		for _, policy := range ast.Policy {
+			line, err := a.savePolicyLine(tx, ptype, policy)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, line)
 		}
-		lines := make([]*ent.CasbinRuleCreate, 0)
+	}
 
-		for ptype, ast := range model["p"] {
-			for _, policy := range ast.Policy {
-				line := a.savePolicyLine(tx, ptype, policy)
-				lines = append(lines, line)
+	for ptype, ast := range model["g"] {
+		for _, policy := range ast.Policy {
+			line, err := a.savePolicyLine(tx, ptype, policy)
+			if err != nil {
+				return err
 			}
+			lines = append(lines, line)
 		}
+	}
+
+	return a.insertBatches(ctx, tx, lines)
+}
 
-		for ptype, ast := range model["g"] {
+// diffPolicy compares the stored rules with the rules of model, then deletes
+// the rules that are no longer in model and inserts the new ones. Rules that
+// did not change keep their rows.
+func (a *Adapter) diffPolicy(ctx context.Context, tx *ent.Tx, model model.Model) error {
+	stored, err := tx.CasbinRule.Query().Order(ent.Asc("id")).All(ctx)
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]int, len(stored))
+	deletes := make([]int, 0)
+	for _, line := range stored {
+		key := ruleKey(line.Ptype, CasbinRuleToStringArray(line))
+		if _, ok := ids[key]; ok {
+			deletes = append(deletes, line.ID)
+			continue
+		}
+		ids[key] = line.ID
+	}
+
+	keep := make(map[string]struct{})
+	lines := make([]*ent.CasbinRuleCreate, 0)
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range model[sec] {
 			for _, policy := range ast.Policy {
-				line := a.savePolicyLine(tx, ptype, policy)
+				key := ruleKey(ptype, policy)
+				if _, ok := keep[key]; ok {
+					continue
+				}
+				keep[key] = struct{}{}
+				if _, ok := ids[key]; ok {
+					continue
+				}
+				line, err := a.savePolicyLine(tx, ptype, policy)
+				if err != nil {
+					return err
+				}
 				lines = append(lines, line)
 			}
 		}
+	}
+	for key, id := range ids {
+		if _, ok := keep[key]; !ok {
+			deletes = append(deletes, id)
+		}
+	}
+	sort.Ints(deletes)
 
-		// batch process
-		batchSize := 5000
-		for i := 0; i < len(lines); i += batchSize {
-			end := i + batchSize
-			if end > len(lines) {
-				end = len(lines)
-			}
-			batch := lines[i:end]
+	if err := a.deleteIDs(ctx, tx, deletes); err != nil {
+		return err
+	}
+	return a.insertBatches(ctx, tx, lines)
+}
 
-			if _, err := tx.CasbinRule.CreateBulk(batch...).Save(a.ctx); err != nil {
-				return err
-			}
+// insertBatches inserts lines in batches that fit the configured batch size
+// and the bind parameter limit of the dialect.
+func (a *Adapter) insertBatches(ctx context.Context, tx *ent.Tx, lines []*ent.CasbinRuleCreate) error {
+	if conn, ok := ctx.Value(copyConnKey{}).(*sql.Conn); ok {
+		// COPY has no parameters, so all lines are sent at once.
+		return a.copyInsert(ctx, conn, lines)
+	}
+	size := a.batchSizeFor(insertColumns)
+	for i := 0; i < len(lines); i += size {
+		end := i + size
+		if end > len(lines) {
+			end = len(lines)
 		}
-		return nil
-	})
+		batch := lines[i:end]
+
+		if err := a.insert(ctx, tx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // AddPolicy adds a policy rule to the storage.
 // This is part of the Auto-Save feature.
 func (a *Adapter) AddPolicy(sec string, ptype string, rule []string) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		_, err := a.savePolicyLine(tx, ptype, rule).Save(a.ctx)
-		return err
+	return a.AddPolicyCtx(a.ctx, sec, ptype, rule)
+}
+
+// AddPolicyCtx adds a policy rule to the storage with context.
+// This is part of the Auto-Save feature.
+func (a *Adapter) AddPolicyCtx(ctx context.Context, sec string, ptype string, rule []string) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		line, err := a.savePolicyLine(tx, ptype, rule)
+		if err != nil {
+			return err
+		}
+		return a.insert(ctx, tx, []*ent.CasbinRuleCreate{line})
 	})
 }
 
 // RemovePolicy removes a policy rule from the storage.
 // This is part of the Auto-Save feature.
 func (a *Adapter) RemovePolicy(sec string, ptype string, rule []string) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		instance := a.toInstance(ptype, rule)
-		_, err := tx.CasbinRule.Delete().Where(
-			casbinrule.PtypeEQ(instance.Ptype),
-			casbinrule.V0EQ(instance.V0),
-			casbinrule.V1EQ(instance.V1),
-			casbinrule.V2EQ(instance.V2),
-			casbinrule.V3EQ(instance.V3),
-			casbinrule.V4EQ(instance.V4),
-			casbinrule.V5EQ(instance.V5),
-		).Exec(a.ctx)
+	return a.RemovePolicyCtx(a.ctx, sec, ptype, rule)
+}
+
+// RemovePolicyCtx removes a policy rule from the storage with context.
+// This is part of the Auto-Save feature.
+func (a *Adapter) RemovePolicyCtx(ctx context.Context, sec string, ptype string, rule []string) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		cond, err := a.rulePredicates(ptype, rule)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CasbinRule.Delete().Where(cond...).Exec(ctx)
 		return err
 	})
 }
@@ -243,30 +844,20 @@ func (a *Adapter) RemovePolicy(sec string, ptype string, rule []string) error {
 // RemoveFilteredPolicy removes policy rules that match the filter from the storage.
 // This is part of the Auto-Save feature.
 func (a *Adapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		cond := make([]predicate.CasbinRule, 0)
-		cond = append(cond, casbinrule.PtypeEQ(ptype))
-		if fieldIndex <= 0 && 0 < fieldIndex+len(fieldValues) && len(fieldValues[0-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V0EQ(fieldValues[0-fieldIndex]))
-		}
-		if fieldIndex <= 1 && 1 < fieldIndex+len(fieldValues) && len(fieldValues[1-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V1EQ(fieldValues[1-fieldIndex]))
-		}
-		if fieldIndex <= 2 && 2 < fieldIndex+len(fieldValues) && len(fieldValues[2-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V2EQ(fieldValues[2-fieldIndex]))
-		}
-		if fieldIndex <= 3 && 3 < fieldIndex+len(fieldValues) && len(fieldValues[3-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V3EQ(fieldValues[3-fieldIndex]))
-		}
-		if fieldIndex <= 4 && 4 < fieldIndex+len(fieldValues) && len(fieldValues[4-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V4EQ(fieldValues[4-fieldIndex]))
-		}
-		if fieldIndex <= 5 && 5 < fieldIndex+len(fieldValues) && len(fieldValues[5-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V5EQ(fieldValues[5-fieldIndex]))
+	return a.RemoveFilteredPolicyCtx(a.ctx, sec, ptype, fieldIndex, fieldValues...)
+}
+
+// RemoveFilteredPolicyCtx removes policy rules that match the filter from the storage with context.
+// This is part of the Auto-Save feature.
+func (a *Adapter) RemoveFilteredPolicyCtx(ctx context.Context, sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		cond, err := filterPredicates(ptype, fieldIndex, fieldValues...)
+		if err != nil {
+			return err
 		}
-		_, err := tx.CasbinRule.Delete().Where(
+		_, err = tx.CasbinRule.Delete().Where(
 			cond...,
-		).Exec(a.ctx)
+		).Exec(ctx)
 		return err
 	})
 }
@@ -274,45 +865,88 @@ func (a *Adapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int,
 // AddPolicies adds policy rules to the storage.
 // This is part of the Auto-Save feature.
 func (a *Adapter) AddPolicies(sec string, ptype string, rules [][]string) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		return a.createPolicies(tx, ptype, rules)
+	return a.AddPoliciesCtx(a.ctx, sec, ptype, rules)
+}
+
+// AddPoliciesCtx adds policy rules to the storage with context.
+// This is part of the Auto-Save feature.
+func (a *Adapter) AddPoliciesCtx(ctx context.Context, sec string, ptype string, rules [][]string) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		return a.createPolicies(ctx, tx, ptype, rules)
 	})
 }
 
 // RemovePolicies removes policy rules from the storage.
 // This is part of the Auto-Save feature.
 func (a *Adapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		for _, rule := range rules {
-			instance := a.toInstance(ptype, rule)
-			if _, err := tx.CasbinRule.Delete().Where(
-				casbinrule.PtypeEQ(instance.Ptype),
-				casbinrule.V0EQ(instance.V0),
-				casbinrule.V1EQ(instance.V1),
-				casbinrule.V2EQ(instance.V2),
-				casbinrule.V3EQ(instance.V3),
-				casbinrule.V4EQ(instance.V4),
-				casbinrule.V5EQ(instance.V5),
-			).Exec(a.ctx); err != nil {
-				return err
-			}
-		}
-		return nil
+	return a.RemovePoliciesCtx(a.ctx, sec, ptype, rules)
+}
+
+// RemovePoliciesCtx removes policy rules from the storage with context.
+// This is part of the Auto-Save feature.
+func (a *Adapter) RemovePoliciesCtx(ctx context.Context, sec string, ptype string, rules [][]string) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		return a.deleteRules(ctx, tx, ptype, rules)
+	})
+}
+
+// WithTx runs fn inside a transaction started with ctx and the configured isolation level.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+// It fails with ErrReadOnly if the adapter is read-only.
+//
+// If ctx carries a transaction, see NewTxContext, fn runs in a savepoint of
+// that transaction instead, which is rolled back to if fn fails. The same
+// holds for the other writes of the adapter, so they can be composed within fn
+// by passing them NewTxContext(ctx, tx).
+func (a *Adapter) WithTx(ctx context.Context, fn func(tx *ent.Tx) error) error {
+	return a.tx(ctx, func(_ context.Context, tx *ent.Tx) error {
+		return fn(tx)
+	})
+}
+
+// tx is like WithTx, but passes fn the context to use within the transaction.
+func (a *Adapter) tx(ctx context.Context, fn func(ctx context.Context, tx *ent.Tx) error) error {
+	if a.readOnly {
+		return ErrReadOnly
+	}
+	joined, err := a.joined(ctx)
+	if err != nil {
+		return err
+	}
+	if joined != nil {
+		return a.savepoint(ctx, joined, fn)
+	}
+	return a.withRetry(ctx, func() error {
+		return a.runTx(ctx, fn)
 	})
 }
 
-func (a *Adapter) WithTx(fn func(tx *ent.Tx) error) error {
-	tx, err := a.client.Tx(a.ctx)
+// runTx runs fn in a new transaction.
+func (a *Adapter) runTx(ctx context.Context, fn func(ctx context.Context, tx *ent.Tx) error) error {
+	client := a.client
+	if a.bulkStrategy == BulkStrategyCopy {
+		// COPY runs on the pgx connection, so the transaction must use it too.
+		conn, err := a.db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		client = ent.NewClient(ent.Driver(a.wrapDriver(newConnDriver(dialect.Postgres, conn))))
+		ctx = context.WithValue(ctx, copyConnKey{}, conn)
+	}
+	tx, err := a.begin(ctx, client)
 	if err != nil {
 		return err
 	}
+	ctx, done := a.withTx(ctx, tx)
+	defer done()
 	defer func() {
 		if v := recover(); v != nil {
 			_ = tx.Rollback()
 			panic(v)
 		}
 	}()
-	if err := fn(tx); err != nil {
+	if err := fn(ctx, tx); err != nil {
 		if rerr := tx.Rollback(); rerr != nil {
 			err = errors.Wrapf(err, "rolling back transaction: %v", rerr)
 		}
@@ -321,32 +955,43 @@ func (a *Adapter) WithTx(fn func(tx *ent.Tx) error) error {
 	if err := tx.Commit(); err != nil {
 		return errors.Wrapf(err, "committing transaction: %v", err)
 	}
+	a.lastWrite.Store(time.Now().UnixNano())
 	return nil
 }
 
-func loadPolicyLine(line *ent.CasbinRule, model model.Model) {
-	var p = []string{line.Ptype,
-		line.V0, line.V1, line.V2, line.V3, line.V4, line.V5}
+// begin starts a transaction on client with the configured isolation level.
+func (a *Adapter) begin(ctx context.Context, client *ent.Client) (*ent.Tx, error) {
+	if a.isolation != sql.LevelDefault {
+		return client.BeginTx(ctx, &sql.TxOptions{Isolation: a.isolation})
+	}
+	return client.Tx(ctx)
+}
 
-	var lineText string
-	if line.V5 != "" {
-		lineText = strings.Join(p, ", ")
-	} else if line.V4 != "" {
-		lineText = strings.Join(p[:6], ", ")
-	} else if line.V3 != "" {
-		lineText = strings.Join(p[:5], ", ")
-	} else if line.V2 != "" {
-		lineText = strings.Join(p[:4], ", ")
-	} else if line.V1 != "" {
-		lineText = strings.Join(p[:3], ", ")
-	} else if line.V0 != "" {
-		lineText = strings.Join(p[:2], ", ")
+func loadPolicyLine(line *ent.CasbinRule, model model.Model) error {
+	rule := CasbinRuleToStringArray(line)
+	if len(rule) == 0 || line.Ptype == "" {
+		return nil
+	}
+	// Rules of assertions the model does not define are skipped, as the
+	// table may be shared with other models.
+	if _, ok := model[line.Ptype[:1]][line.Ptype]; !ok {
+		return nil
 	}
+	return persist.LoadPolicyArray(append([]string{line.Ptype}, rule...), model)
+}
 
-	persist.LoadPolicyLine(lineText, model)
+// checkRule returns an error if rule has more fields than can be stored.
+func checkRule(rule []string) error {
+	if len(rule) > MaxFields {
+		return errors.Wrapf(ErrTooManyFields, "rule %v has %d fields, at most %d are supported", rule, len(rule), MaxFields)
+	}
+	return nil
 }
 
-func (a *Adapter) toInstance(ptype string, rule []string) *ent.CasbinRule {
+func (a *Adapter) toInstance(ptype string, rule []string) (*ent.CasbinRule, error) {
+	if err := checkRule(rule); err != nil {
+		return nil, err
+	}
 	instance := &ent.CasbinRule{}
 
 	instance.Ptype = ptype
@@ -369,13 +1014,58 @@ func (a *Adapter) toInstance(ptype string, rule []string) *ent.CasbinRule {
 	if len(rule) > 5 {
 		instance.V5 = rule[5]
 	}
-	return instance
+	if len(rule) > 6 {
+		instance.V6 = rule[6]
+	}
+	if len(rule) > 7 {
+		instance.V7 = rule[7]
+	}
+	if len(rule) > 8 {
+		instance.V8 = rule[8]
+	}
+	if len(rule) > 9 {
+		instance.V9 = rule[9]
+	}
+	return instance, nil
+}
+
+// rulePredicates returns the predicates that match exactly the given rule.
+// Rules are matched by their hash, which covers the arity as well, so a rule
+// does not match the same rule with trailing empty fields.
+func (a *Adapter) rulePredicates(ptype string, rule []string) ([]predicate.CasbinRule, error) {
+	if err := checkRule(rule); err != nil {
+		return nil, err
+	}
+	return []predicate.CasbinRule{casbinrule.RuleHashEQ(ruleHash(ptype, rule))}, nil
 }
 
-func (a *Adapter) savePolicyLine(tx *ent.Tx, ptype string, rule []string) *ent.CasbinRuleCreate {
+// filterPredicates returns the predicates that match the rules of ptype whose
+// fields, starting at fieldIndex, equal the non-empty fieldValues.
+func filterPredicates(ptype string, fieldIndex int, fieldValues ...string) ([]predicate.CasbinRule, error) {
+	cond := make([]predicate.CasbinRule, 0)
+	cond = append(cond, casbinrule.PtypeEQ(ptype))
+	for i, value := range fieldValues {
+		index := fieldIndex + i
+		if index < 0 || value == "" {
+			continue
+		}
+		if index >= MaxFields {
+			return nil, errors.Wrapf(ErrTooManyFields, "filter on field %d, at most %d are supported", index, MaxFields)
+		}
+		cond = append(cond, predicate.CasbinRule(entsql.FieldEQ(fieldColumns[index], value)))
+	}
+	return cond, nil
+}
+
+func (a *Adapter) savePolicyLine(tx *ent.Tx, ptype string, rule []string) (*ent.CasbinRuleCreate, error) {
+	if err := checkRule(rule); err != nil {
+		return nil, err
+	}
 	line := tx.CasbinRule.Create()
 
 	line.SetPtype(ptype)
+	line.SetArity(len(rule))
+	line.SetRuleHash(ruleHash(ptype, rule))
 	if len(rule) > 0 {
 		line.SetV0(rule[0])
 	}
@@ -394,91 +1084,92 @@ func (a *Adapter) savePolicyLine(tx *ent.Tx, ptype string, rule []string) *ent.C
 	if len(rule) > 5 {
 		line.SetV5(rule[5])
 	}
+	if len(rule) > 6 {
+		line.SetV6(rule[6])
+	}
+	if len(rule) > 7 {
+		line.SetV7(rule[7])
+	}
+	if len(rule) > 8 {
+		line.SetV8(rule[8])
+	}
+	if len(rule) > 9 {
+		line.SetV9(rule[9])
+	}
 
-	return line
+	return line, nil
 }
 
 // UpdatePolicy updates a policy rule from storage.
 // This is part of the Auto-Save feature.
 func (a *Adapter) UpdatePolicy(sec string, ptype string, oldRule, newPolicy []string) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		rule := a.toInstance(ptype, oldRule)
-		line := tx.CasbinRule.Update().Where(
-			casbinrule.PtypeEQ(rule.Ptype),
-			casbinrule.V0EQ(rule.V0),
-			casbinrule.V1EQ(rule.V1),
-			casbinrule.V2EQ(rule.V2),
-			casbinrule.V3EQ(rule.V3),
-			casbinrule.V4EQ(rule.V4),
-			casbinrule.V5EQ(rule.V5),
-		)
-		rule = a.toInstance(ptype, newPolicy)
+	return a.UpdatePolicyCtx(a.ctx, sec, ptype, oldRule, newPolicy)
+}
+
+// UpdatePolicyCtx updates a policy rule from storage with context.
+// This is part of the Auto-Save feature.
+func (a *Adapter) UpdatePolicyCtx(ctx context.Context, sec string, ptype string, oldRule, newPolicy []string) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		cond, err := a.rulePredicates(ptype, oldRule)
+		if err != nil {
+			return err
+		}
+		line := tx.CasbinRule.Update().Where(cond...)
+		rule, err := a.toInstance(ptype, newPolicy)
+		if err != nil {
+			return err
+		}
 		line.SetV0(rule.V0)
 		line.SetV1(rule.V1)
 		line.SetV2(rule.V2)
 		line.SetV3(rule.V3)
 		line.SetV4(rule.V4)
 		line.SetV5(rule.V5)
-		_, err := line.Save(a.ctx)
+		line.SetV6(rule.V6)
+		line.SetV7(rule.V7)
+		line.SetV8(rule.V8)
+		line.SetV9(rule.V9)
+		line.SetArity(len(newPolicy))
+		line.SetRuleHash(ruleHash(ptype, newPolicy))
+		_, err = line.Save(ctx)
+		if ent.IsConstraintError(err) {
+			return ErrPolicyExists
+		}
 		return err
 	})
 }
 
 // UpdatePolicies updates some policy rules to storage, like db, redis.
 func (a *Adapter) UpdatePolicies(sec string, ptype string, oldRules, newRules [][]string) error {
-	return a.WithTx(func(tx *ent.Tx) error {
-		for _, policy := range oldRules {
-			rule := a.toInstance(ptype, policy)
-			if _, err := tx.CasbinRule.Delete().Where(
-				casbinrule.PtypeEQ(rule.Ptype),
-				casbinrule.V0EQ(rule.V0),
-				casbinrule.V1EQ(rule.V1),
-				casbinrule.V2EQ(rule.V2),
-				casbinrule.V3EQ(rule.V3),
-				casbinrule.V4EQ(rule.V4),
-				casbinrule.V5EQ(rule.V5),
-			).Exec(a.ctx); err != nil {
-				return err
-			}
-		}
-		lines := make([]*ent.CasbinRuleCreate, 0)
-		for _, policy := range newRules {
-			lines = append(lines, a.savePolicyLine(tx, ptype, policy))
-		}
-		if _, err := tx.CasbinRule.CreateBulk(lines...).Save(a.ctx); err != nil {
+	return a.UpdatePoliciesCtx(a.ctx, sec, ptype, oldRules, newRules)
+}
+
+// UpdatePoliciesCtx updates some policy rules to storage with context.
+func (a *Adapter) UpdatePoliciesCtx(ctx context.Context, sec string, ptype string, oldRules, newRules [][]string) error {
+	return a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		if err := a.deleteRules(ctx, tx, ptype, oldRules); err != nil {
 			return err
 		}
-		return nil
+		return a.createPolicies(ctx, tx, ptype, newRules)
 	})
 }
 
 // UpdateFilteredPolicies deletes old rules and adds new rules.
 func (a *Adapter) UpdateFilteredPolicies(sec string, ptype string, newPolicies [][]string, fieldIndex int, fieldValues ...string) ([][]string, error) {
-	oldPolicies := make([][]string, 0)
-	err := a.WithTx(func(tx *ent.Tx) error {
-		cond := make([]predicate.CasbinRule, 0)
-		cond = append(cond, casbinrule.PtypeEQ(ptype))
-		if fieldIndex <= 0 && 0 < fieldIndex+len(fieldValues) && len(fieldValues[0-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V0EQ(fieldValues[0-fieldIndex]))
-		}
-		if fieldIndex <= 1 && 1 < fieldIndex+len(fieldValues) && len(fieldValues[1-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V1EQ(fieldValues[1-fieldIndex]))
-		}
-		if fieldIndex <= 2 && 2 < fieldIndex+len(fieldValues) && len(fieldValues[2-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V2EQ(fieldValues[2-fieldIndex]))
-		}
-		if fieldIndex <= 3 && 3 < fieldIndex+len(fieldValues) && len(fieldValues[3-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V3EQ(fieldValues[3-fieldIndex]))
-		}
-		if fieldIndex <= 4 && 4 < fieldIndex+len(fieldValues) && len(fieldValues[4-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V4EQ(fieldValues[4-fieldIndex]))
-		}
-		if fieldIndex <= 5 && 5 < fieldIndex+len(fieldValues) && len(fieldValues[5-fieldIndex]) > 0 {
-			cond = append(cond, casbinrule.V5EQ(fieldValues[5-fieldIndex]))
+	return a.UpdateFilteredPoliciesCtx(a.ctx, sec, ptype, newPolicies, fieldIndex, fieldValues...)
+}
+
+// UpdateFilteredPoliciesCtx deletes old rules and adds new rules with context.
+func (a *Adapter) UpdateFilteredPoliciesCtx(ctx context.Context, sec string, ptype string, newPolicies [][]string, fieldIndex int, fieldValues ...string) ([][]string, error) {
+	var oldPolicies [][]string
+	err := a.tx(ctx, func(ctx context.Context, tx *ent.Tx) error {
+		cond, err := filterPredicates(ptype, fieldIndex, fieldValues...)
+		if err != nil {
+			return err
 		}
 		rules, err := tx.CasbinRule.Query().
 			Where(cond...).
-			All(a.ctx)
+			All(ctx)
 		if err != nil {
 			return err
 		}
@@ -487,19 +1178,19 @@ func (a *Adapter) UpdateFilteredPolicies(sec string, ptype string, newPolicies [
 			ruleIDs = append(ruleIDs, r.ID)
 		}
 
-		_, err = tx.CasbinRule.Delete().
-			Where(casbinrule.IDIn(ruleIDs...)).
-			Exec(a.ctx)
-		if err != nil {
+		if err := a.deleteIDs(ctx, tx, ruleIDs); err != nil {
 			return err
 		}
 
-		if err := a.createPolicies(tx, ptype, newPolicies); err != nil {
+		if err := a.createPolicies(ctx, tx, ptype, newPolicies); err != nil {
 			return err
 		}
+		// The transaction may be retried, so the result is only set by the run that succeeds.
+		old := make([][]string, 0, len(rules))
 		for _, rule := range rules {
-			oldPolicies = append(oldPolicies, CasbinRuleToStringArray(rule))
+			old = append(old, CasbinRuleToStringArray(rule))
 		}
+		oldPolicies = old
 		return nil
 	})
 	if err != nil {
@@ -508,36 +1199,139 @@ func (a *Adapter) UpdateFilteredPolicies(sec string, ptype string, newPolicies [
 	return oldPolicies, nil
 }
 
-func (a *Adapter) createPolicies(tx *ent.Tx, ptype string, policies [][]string) error {
+func (a *Adapter) createPolicies(ctx context.Context, tx *ent.Tx, ptype string, policies [][]string) error {
 	lines := make([]*ent.CasbinRuleCreate, 0)
+	seen := make(map[string]struct{})
 	for _, policy := range policies {
-		lines = append(lines, a.savePolicyLine(tx, ptype, policy))
+		// A statement may not resolve a conflict on the same row twice,
+		// so duplicates are dropped up front unless they should fail.
+		if a.onConflict != OnConflictError {
+			key := ruleKey(ptype, policy)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		line, err := a.savePolicyLine(tx, ptype, policy)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
 	}
-	if _, err := tx.CasbinRule.CreateBulk(lines...).Save(a.ctx); err != nil {
-		return err
+	return a.insertBatches(ctx, tx, lines)
+}
+
+// deleteRules deletes the given rules of ptype with one statement per batch,
+// matching any of the rules exactly.
+func (a *Adapter) deleteRules(ctx context.Context, tx *ent.Tx, ptype string, rules [][]string) error {
+	size := a.batchSizeFor(1)
+	for i := 0; i < len(rules); i += size {
+		end := i + size
+		if end > len(rules) {
+			end = len(rules)
+		}
+		hashes := make([]string, 0, end-i)
+		for _, rule := range rules[i:end] {
+			if err := checkRule(rule); err != nil {
+				return err
+			}
+			hashes = append(hashes, ruleHash(ptype, rule))
+		}
+		if _, err := tx.CasbinRule.Delete().Where(casbinrule.RuleHashIn(hashes...)).Exec(ctx); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func CasbinRuleToStringArray(rule *ent.CasbinRule) []string {
-	arr := make([]string, 0)
-	if rule.V0 != "" {
-		arr = append(arr, rule.V0)
+// deleteIDs deletes the rules with the given ids in batches that fit the
+// configured batch size and the bind parameter limit of the dialect.
+func (a *Adapter) deleteIDs(ctx context.Context, tx *ent.Tx, ids []int) error {
+	size := a.batchSizeFor(1)
+	for i := 0; i < len(ids); i += size {
+		end := i + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if _, err := tx.CasbinRule.Delete().Where(casbinrule.IDIn(ids[i:end]...)).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchSizeFor returns the number of rows with the given number of bind
+// parameters each that can be sent in one statement.
+func (a *Adapter) batchSizeFor(params int) int {
+	size := maxParams(a.client.Driver().Dialect()) / params
+	if size > a.batchSize {
+		size = a.batchSize
+	}
+	return size
+}
+
+// maxParams returns the maximum number of bind parameters of a statement.
+func maxParams(name string) int {
+	switch name {
+	case dialect.MySQL, dialect.Postgres:
+		return 65535
+	default:
+		// SQLite before 3.32 allows 999 parameters, later versions 32766.
+		return 999
 	}
-	if rule.V1 != "" {
-		arr = append(arr, rule.V1)
+}
+
+// ruleKey returns a key that identifies the rule of ptype with the given fields.
+func ruleKey(ptype string, rule []string) string {
+	return ptype + "\x00" + strings.Join(rule, "\x00")
+}
+
+// ruleHash returns the hash that identifies the rule of ptype with the given
+// fields. Every value is prefixed with its length, so that different rules,
+// e.g. with and without a trailing empty field, cannot be encoded alike.
+func ruleHash(ptype string, rule []string) string {
+	h := sha256.New()
+	var buf [binary.MaxVarintLen64]byte
+	for _, v := range append([]string{ptype}, rule...) {
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(v)))])
+		h.Write([]byte(v))
 	}
-	if rule.V2 != "" {
-		arr = append(arr, rule.V2)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// insert stores lines, resolving rules that are already stored
+// according to the configured OnConflict mode.
+func (a *Adapter) insert(ctx context.Context, tx *ent.Tx, lines []*ent.CasbinRuleCreate) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	bulk := tx.CasbinRule.CreateBulk(lines...)
+	var err error
+	switch a.onConflict {
+	case OnConflictIgnore:
+		err = bulk.OnConflict(conflictColumns).Ignore().Exec(ctx)
+	case OnConflictUpsert:
+		err = bulk.OnConflict(conflictColumns).UpdateNewValues().Exec(ctx)
+	default:
+		err = bulk.Exec(ctx)
 	}
-	if rule.V3 != "" {
-		arr = append(arr, rule.V3)
+	if ent.IsConstraintError(err) {
+		return ErrPolicyExists
 	}
-	if rule.V4 != "" {
-		arr = append(arr, rule.V4)
+	return err
+}
+
+// CasbinRuleToStringArray returns the fields of a stored rule, including empty ones.
+// Rules stored by older versions have no arity, so their trailing empty fields are dropped.
+func CasbinRuleToStringArray(rule *ent.CasbinRule) []string {
+	arr := []string{rule.V0, rule.V1, rule.V2, rule.V3, rule.V4,
+		rule.V5, rule.V6, rule.V7, rule.V8, rule.V9}
+	if rule.Arity != nil && *rule.Arity >= 0 && *rule.Arity <= len(arr) {
+		return arr[:*rule.Arity]
 	}
-	if rule.V5 != "" {
-		arr = append(arr, rule.V5)
+	end := len(arr)
+	for end > 0 && arr[end-1] == "" {
+		end--
 	}
-	return arr
+	return arr[:end]
 }
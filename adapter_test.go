@@ -15,14 +15,33 @@
 package entadapter
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"math/rand/v2"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/schema"
 	"github.com/casbin/casbin/v3"
+	"github.com/casbin/casbin/v3/model"
 	"github.com/casbin/casbin/v3/util"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/casbin/ent-adapter/ent"
+	"github.com/casbin/ent-adapter/ent/casbinrule"
+	"github.com/casbin/ent-adapter/ent/migrate"
 )
 
 func testGetPolicy(t *testing.T, e *casbin.Enforcer, res [][]string) {
@@ -126,6 +145,16 @@ func testSaveLoad(t *testing.T, a *Adapter) {
 	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
 }
 
+// openClient opens a client like ent.Open, but also for the driver names
+// that are mapped to another dialect, such as pgx and sqlite.
+func openClient(t *testing.T, driverName string, dataSourceName string, options ...ent.Option) *ent.Client {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		panic(err)
+	}
+	return ent.NewClient(append([]ent.Option{ent.Driver(openDB(driverName, db))}, options...)...)
+}
+
 func initAdapter(t *testing.T, driverName string, dataSourceName string, options ...Option) *Adapter {
 	// Create an adapter
 	a, err := NewAdapter(driverName, dataSourceName, options...)
@@ -275,6 +304,992 @@ func testFilteredPolicy(t *testing.T, a *Adapter) {
 	// Load policies for alice and bob
 	assert.Nil(t, e.LoadFilteredPolicy(Filter{V0: []string{"alice", "bob"}}))
 	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}})
+
+	assert.Nil(t, e.LoadFilteredPolicy(&Filter{V0: []string{"bob"}}))
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}})
+
+	// Several filters are combined with OR.
+	assert.Nil(t, e.LoadFilteredPolicy([]Filter{{V0: []string{"alice"}, V1: []string{"data1"}}, {V2: []string{"write"}}}))
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "write"}})
+
+	assert.Nil(t, e.LoadFilteredPolicy(BatchFilter{Filters: []Filter{{Ptype: []string{"g"}}, {V0: []string{"bob"}}}}))
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}})
+	g, err := e.GetGroupingPolicy()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"alice", "data2_admin"}}, g)
+
+	assert.Nil(t, e.LoadFilteredPolicy(BatchFilter{}))
+	testGetPolicy(t, e, [][]string{})
+
+	assert.NotNil(t, e.LoadFilteredPolicy((*Filter)(nil)))
+	assert.NotNil(t, e.LoadFilteredPolicy([]string{"alice"}))
+}
+
+func testIncrementalFilteredPolicy(t *testing.T, a *Adapter) {
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", "examples/rbac_policy.csv")
+	e.SetAdapter(a)
+	// A full load resets the filtered flag left by earlier tests.
+	assert.Nil(t, e.LoadPolicy())
+	assert.False(t, e.IsFiltered())
+	assert.Nil(t, e.SavePolicy())
+
+	assert.Nil(t, e.LoadFilteredPolicy(Filter{V0: []string{"alice"}}))
+	assert.True(t, e.IsFiltered())
+	assert.NotNil(t, e.SavePolicy())
+
+	// Rules that are already loaded are not added twice.
+	assert.Nil(t, e.LoadIncrementalFilteredPolicy(Filter{V0: []string{"alice", "data2_admin"}}))
+	assert.Nil(t, e.LoadIncrementalFilteredPolicy(Filter{Ptype: []string{"g"}}))
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+	ok, err := e.Enforce("alice", "data2", "write")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = e.Enforce("bob", "data2", "write")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// A full load clears the filtered flag again.
+	assert.Nil(t, e.LoadPolicy())
+	assert.False(t, e.IsFiltered())
+	assert.Nil(t, e.SavePolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+}
+
+func testFilterExpr(t *testing.T, a *Adapter) {
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", "examples/rbac_policy.csv")
+	e.SetAdapter(a)
+	assert.Nil(t, e.SavePolicy())
+
+	assert.Nil(t, e.LoadFilteredPolicy(HasPrefix("v0", "data2")))
+	testGetPolicy(t, e, [][]string{{"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+	// "_" and "%" are matched literally by globs.
+	assert.Nil(t, e.LoadFilteredPolicy(Glob("v0", "data_?admin")))
+	testGetPolicy(t, e, [][]string{})
+	assert.Nil(t, e.LoadFilteredPolicy(Glob("v0", "data?_admin")))
+	testGetPolicy(t, e, [][]string{{"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+	assert.Nil(t, e.LoadFilteredPolicy(And(In("ptype", "p"), NotIn("v0", "alice", "data2_admin"))))
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}})
+
+	assert.Nil(t, e.LoadFilteredPolicy(Or(And(Glob("v1", "data*"), Not(Contains("v2", "write"))), In("ptype", "g"))))
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"data2_admin", "data2", "read"}})
+	g, err := e.GetGroupingPolicy()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"alice", "data2_admin"}}, g)
+
+	assert.Nil(t, e.LoadFilteredPolicy(Where(casbinrule.V2EQ("write"))))
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "write"}})
+
+	assert.NotNil(t, e.LoadFilteredPolicy(In("v10", "alice")))
+	assert.NotNil(t, e.LoadFilteredPolicy(Or(In("ptype", "p"), HasSuffix("id", "1"))))
+
+	// An empty Or matches nothing, an empty Where or And is rejected.
+	assert.Nil(t, e.LoadFilteredPolicy(Or()))
+	testGetPolicy(t, e, [][]string{})
+	g, err = e.GetGroupingPolicy()
+	assert.Nil(t, err)
+	assert.Empty(t, g)
+	assert.Nil(t, e.LoadFilteredPolicy(Or(Or(), In("v0", "bob"))))
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}})
+	assert.NotNil(t, e.LoadFilteredPolicy(Where()))
+	assert.NotNil(t, e.LoadFilteredPolicy(And()))
+	assert.NotNil(t, e.LoadFilteredPolicy(Not(And())))
+
+	// So is the zero FilterExpr, on its own or combined.
+	assert.NotNil(t, e.LoadFilteredPolicy(FilterExpr{}))
+	assert.NotNil(t, e.LoadFilteredPolicy(Not(FilterExpr{})))
+	assert.NotNil(t, e.LoadFilteredPolicy(Or(FilterExpr{}, In("v0", "bob"))))
+	assert.NotNil(t, e.LoadFilteredPolicy(And(FilterExpr{}, In("v0", "bob"))))
+
+	// Regular expressions are only supported by MySQL and PostgreSQL.
+	switch a.client.Driver().Dialect() {
+	case dialect.MySQL, dialect.Postgres:
+		assert.Nil(t, e.LoadFilteredPolicy(Regex("v0", "^(alice|bob)$")))
+		testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}})
+	default:
+		assert.NotNil(t, e.LoadFilteredPolicy(Regex("v0", "^(alice|bob)$")))
+	}
+}
+
+func testContext(t *testing.T, a *Adapter) {
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A canceled context must abort the operation without touching the storage.
+	assert.NotNil(t, a.AddPolicyCtx(ctx, "p", "p", []string{"carol", "data3", "read"}))
+	assert.NotNil(t, a.LoadPolicyCtx(ctx, e.GetModel()))
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.Nil(t, a.AddPolicyCtx(ctx, "p", "p", []string{"carol", "data3", "read"}))
+	assert.Nil(t, a.RemovePolicyCtx(ctx, "p", "p", []string{"carol", "data3", "read"}))
+	e.ClearPolicy()
+	assert.Nil(t, a.LoadPolicyCtx(ctx, e.GetModel()))
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+}
+
+func testTableName(t *testing.T, driverName string, dataSourceName string) {
+	a := initAdapter(t, driverName, dataSourceName, WithTableName("casbin_rule_custom"))
+	b, err := NewAdapter(driverName, dataSourceName, WithTablePrefix("app2_"))
+	assert.Nil(t, err)
+
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf")
+	_, _ = e.AddPolicy("carol", "data3", "read")
+	assert.Nil(t, b.SavePolicy(e.GetModel()))
+
+	// Each adapter only sees the rules in its own table.
+	e, _ = casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+	e, _ = casbin.NewEnforcer("examples/rbac_model.conf", b)
+	testGetPolicy(t, e, [][]string{{"carol", "data3", "read"}})
+
+	_, err = NewAdapter(driverName, dataSourceName, WithTableName("casbin rule"))
+	assert.NotNil(t, err)
+	_, err = NewAdapter(driverName, dataSourceName, WithTablePrefix("app-"))
+	assert.NotNil(t, err)
+}
+
+func testOnConflict(t *testing.T, driverName string, dataSourceName string) {
+	a := initAdapter(t, driverName, dataSourceName)
+
+	// Adding a stored rule fails by default and leaves the storage untouched.
+	assert.ErrorIs(t, a.AddPolicy("p", "p", []string{"alice", "data1", "read"}), ErrPolicyExists)
+	assert.ErrorIs(t, a.AddPolicies("p", "p", [][]string{{"carol", "data3", "read"}, {"bob", "data2", "write"}}), ErrPolicyExists)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+	for _, mode := range []OnConflict{OnConflictIgnore, OnConflictUpsert} {
+		a = initAdapter(t, driverName, dataSourceName, WithOnConflict(mode))
+		assert.Nil(t, a.AddPolicy("p", "p", []string{"alice", "data1", "read"}))
+		assert.Nil(t, a.AddPolicies("p", "p", [][]string{{"carol", "data3", "read"}, {"bob", "data2", "write"}, {"carol", "data3", "read"}}))
+		e, _ = casbin.NewEnforcer("examples/rbac_model.conf", a)
+		testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}})
+	}
+
+	_, err := NewAdapter(driverName, dataSourceName, WithOnConflict(OnConflict(42)))
+	assert.NotNil(t, err)
+}
+
+func testRemoveDuplicates(t *testing.T, client *ent.Client) {
+	ctx := context.Background()
+
+	// Create a table without the unique index, as older versions did, and store a rule twice.
+	table := *migrate.CasbinRuleTable
+	table.Name = "casbin_rule_dup"
+	table.Indexes = nil
+	assert.Nil(t, migrate.Create(ctx, client.Schema, []*schema.Table{&table}))
+	drv := client.Driver()
+	b := entsql.Dialect(drv.Dialect())
+	query, args := b.Delete(table.Name).Query()
+	assert.Nil(t, drv.Exec(ctx, query, args, nil))
+	for i := 0; i < 2; i++ {
+		query, args = b.Insert(table.Name).
+			Columns(casbinrule.FieldPtype, casbinrule.FieldV0, casbinrule.FieldV1, casbinrule.FieldV2).
+			Values("p", "alice", "data1", "read").
+			Query()
+		assert.Nil(t, drv.Exec(ctx, query, args, nil))
+	}
+
+	a, err := NewAdapterWithClient(client, WithTableName(table.Name))
+	assert.Nil(t, err)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}})
+	assert.ErrorIs(t, a.AddPolicy("p", "p", []string{"alice", "data1", "read"}), ErrPolicyExists)
+
+	// The unique index of older versions on the rule columns is dropped.
+	query, args = b.Delete(table.Name).Query()
+	assert.Nil(t, drv.Exec(ctx, query, args, nil))
+	assert.Nil(t, drv.Exec(ctx, "DROP TABLE "+table.Name, []any{}, nil))
+	table.Indexes = []*schema.Index{{
+		Name:    "idx_" + table.Name,
+		Unique:  true,
+		Columns: []*schema.Column{migrate.CasbinRuleColumns[1], migrate.CasbinRuleColumns[2]},
+	}}
+	assert.Nil(t, migrate.Create(ctx, client.Schema, []*schema.Table{&table}))
+	a, err = NewAdapterWithClient(client, WithTableName(table.Name))
+	assert.Nil(t, err)
+	assert.Nil(t, a.AddPolicies("p", "p", [][]string{{"alice", "data1", "read"}, {"alice", "data2", "read"}}))
+}
+
+func testLongRules(t *testing.T, driverName string, dataSourceName string) {
+	// MySQL cannot index long columns whole, these rules only differ after the first 100 characters.
+	prefix := strings.Repeat("x", 100)
+	rules := [][]string{{prefix + "alice", "data1", "read"}, {prefix + "bob", "data1", "read"}}
+	initial := [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}}
+
+	a := initAdapter(t, driverName, dataSourceName)
+	assert.Nil(t, a.AddPolicy("p", "p", rules[0]))
+	assert.Nil(t, a.AddPolicy("p", "p", rules[1]))
+	assert.ErrorIs(t, a.AddPolicy("p", "p", rules[1]), ErrPolicyExists)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, append(slices.Clone(initial), rules...))
+
+	for _, mode := range []OnConflict{OnConflictIgnore, OnConflictUpsert} {
+		a = initAdapter(t, driverName, dataSourceName, WithOnConflict(mode))
+		assert.Nil(t, a.AddPolicy("p", "p", rules[0]))
+		assert.Nil(t, a.AddPolicies("p", "p", rules))
+		e, _ = casbin.NewEnforcer("examples/rbac_model.conf", a)
+		testGetPolicy(t, e, append(slices.Clone(initial), rules...))
+	}
+
+	// Saving a policy with both rules stores both.
+	assert.Nil(t, a.SavePolicy(e.GetModel()))
+	e.ClearPolicy()
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, append(slices.Clone(initial), rules...))
+
+	// Fields too long for an index entry are stored as well, except on MySQL,
+	// whose columns hold 255 characters. Random ones do not compress.
+	if a.client.Driver().Dialect() != dialect.MySQL {
+		r := rand.New(rand.NewPCG(1, 2))
+		field := make([]byte, 8000)
+		for i := range field {
+			field[i] = byte('!' + r.IntN(94))
+		}
+		rule := []string{string(field), "data1", "read"}
+		assert.Nil(t, a.AddPolicy("p", "p", rule))
+		assert.Nil(t, e.LoadPolicy())
+		testGetPolicy(t, e, append(slices.Clone(initial), append(rules, rule)...))
+		assert.Nil(t, a.RemovePolicy("p", "p", rule))
+	}
+}
+
+func testLegacyTable(t *testing.T, driverName string, dataSourceName string) {
+	// The table of older versions is not replaced by an empty default table.
+	a, err := NewAdapter(driverName, dataSourceName, WithTableName("casbin_rules"))
+	assert.Nil(t, err)
+	assert.Nil(t, a.AddPolicy("p", "p", []string{"alice", "data1", "read"}))
+	assert.Nil(t, a.Close())
+	_, err = NewAdapter(driverName, dataSourceName)
+	assert.ErrorIs(t, err, ErrLegacyTable)
+
+	a, err = NewAdapter(driverName, dataSourceName, WithTableName("casbin_rules"))
+	assert.Nil(t, err)
+	defer a.Close()
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}})
+}
+
+func testOptions(t *testing.T, driverName string, dataSourceName string) {
+	for _, option := range []Option{
+		WithTableName(""),
+		WithOnConflict(OnConflict(42)),
+		WithBatchSize(0),
+		WithLogger(nil),
+		WithContext(nil),
+		WithTxIsolation(sql.IsolationLevel(42)),
+		WithSaveMode(SaveMode(42)),
+		WithPageSize(0),
+		WithParallelLoad(0),
+		WithReplica(nil),
+		WithReplicaDB(nil),
+		WithReadYourWrites(0),
+		WithReadYourWrites(time.Second),
+		WithRetry(RetryPolicy{}),
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: -time.Second}),
+	} {
+		_, err := NewAdapter(driverName, dataSourceName, option)
+		assert.NotNil(t, err)
+	}
+	_, err := NewAdapter(driverName, dataSourceName, WithReadOnly(), WithBatchSize(10))
+	assert.NotNil(t, err)
+	_, err = NewAdapter(driverName, dataSourceName, WithReadOnly(), WithOnConflict(OnConflictIgnore))
+	assert.NotNil(t, err)
+	_, err = NewAdapter(driverName, dataSourceName, WithReadOnly(), WithSaveMode(SaveModeDiff))
+	assert.NotNil(t, err)
+	_, err = NewAdapter(driverName, dataSourceName, WithSnapshotLoad(), WithParallelLoad(2))
+	assert.NotNil(t, err)
+
+	// Every statement goes through the logger, and none is issued without auto-migration.
+	statements := 0
+	logger := func(...any) { statements++ }
+	_, err = NewAdapter(driverName, dataSourceName, WithoutAutoMigrate(), WithLogger(logger))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, statements)
+	a := initAdapter(t, driverName, dataSourceName, WithBatchSize(1), WithLogger(logger), WithTxIsolation(sql.LevelSerializable))
+	assert.NotEqual(t, 0, statements)
+	testAutoSave(t, a)
+
+	initAdapter(t, driverName, dataSourceName)
+	r, err := NewAdapter(driverName, dataSourceName, WithReadOnly())
+	assert.Nil(t, err)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", r)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+	assert.ErrorIs(t, r.AddPolicy("p", "p", []string{"carol", "data3", "read"}), ErrReadOnly)
+	assert.ErrorIs(t, r.SavePolicy(e.GetModel()), ErrReadOnly)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c, err := NewAdapter(driverName, dataSourceName, WithContext(ctx), WithoutAutoMigrate())
+	assert.Nil(t, err)
+	assert.NotNil(t, c.LoadPolicy(e.GetModel()))
+}
+
+func testSaveMode(t *testing.T, driverName string, dataSourceName string) {
+	ctx := context.Background()
+	ids := func(a *Adapter) map[string]int {
+		lines, err := a.client.CasbinRule.Query().All(ctx)
+		assert.Nil(t, err)
+		res := make(map[string]int)
+		for _, line := range lines {
+			res[ruleKey(line.Ptype, CasbinRuleToStringArray(line))] = line.ID
+		}
+		return res
+	}
+
+	// Only the changed rules are written, the others keep their rows.
+	a := initAdapter(t, driverName, dataSourceName, WithBatchSize(1), WithSaveMode(SaveModeDiff))
+	before := ids(a)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	e.EnableAutoSave(false)
+	_, err := e.RemovePolicy("bob", "data2", "write")
+	assert.Nil(t, err)
+	_, err = e.AddPolicy("carol", "data3", "read")
+	assert.Nil(t, err)
+	assert.Nil(t, e.SavePolicy())
+	after := ids(a)
+	assert.Equal(t, before[ruleKey("p", []string{"alice", "data1", "read"})], after[ruleKey("p", []string{"alice", "data1", "read"})])
+	assert.Equal(t, before[ruleKey("g", []string{"alice", "data2_admin"})], after[ruleKey("g", []string{"alice", "data2_admin"})])
+	assert.NotContains(t, after, ruleKey("p", []string{"bob", "data2", "write"}))
+	assert.Contains(t, after, ruleKey("p", []string{"carol", "data3", "read"}))
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicyWithoutOrder(t, e, [][]string{{"alice", "data1", "read"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}})
+
+	// Saving an unchanged policy writes nothing.
+	assert.Nil(t, e.SavePolicy())
+	assert.Equal(t, after, ids(a))
+
+	// The whole table is rewritten by default.
+	r := initAdapter(t, driverName, dataSourceName)
+	before = ids(r)
+	e, _ = casbin.NewEnforcer("examples/rbac_model.conf", r)
+	assert.Nil(t, e.SavePolicy())
+	after = ids(r)
+	assert.Equal(t, len(before), len(after))
+	for key, id := range before {
+		assert.NotEqual(t, id, after[key])
+	}
+}
+
+func testBulk(t *testing.T, a *Adapter) {
+	// More rules than fit in one statement of any dialect.
+	rules := make([][]string, 0, 7000)
+	for i := 0; i < cap(rules); i++ {
+		rules = append(rules, []string{fmt.Sprintf("user%d", i), "bulk", "read"})
+	}
+	assert.Nil(t, a.AddPolicies("p", "p", rules))
+	count, err := a.client.CasbinRule.Query().Where(casbinrule.V1EQ("bulk")).Count(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, len(rules), count)
+
+	updated := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		updated = append(updated, []string{rule[0], "bulk", "write"})
+	}
+	old, err := a.UpdateFilteredPolicies("p", "p", updated, 1, "bulk")
+	assert.Nil(t, err)
+	assert.Equal(t, len(rules), len(old))
+	assert.Nil(t, a.UpdatePolicies("p", "p", updated, rules))
+	count, err = a.client.CasbinRule.Query().Where(casbinrule.V2EQ("read"), casbinrule.V1EQ("bulk")).Count(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, len(rules), count)
+
+	assert.Nil(t, a.RemovePolicies("p", "p", rules))
+	count, err = a.client.CasbinRule.Query().Where(casbinrule.V1EQ("bulk")).Count(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func testBulkStrategy(t *testing.T, driverName string, dataSourceName string, strategy BulkStrategy) {
+	if strategy != BulkStrategyCopy {
+		_, err := NewAdapter(driverName, dataSourceName, WithBulkStrategy(BulkStrategyCopy))
+		assert.NotNil(t, err)
+	}
+	if strategy == BulkStrategyInsert {
+		return
+	}
+
+	a := initAdapter(t, driverName, dataSourceName, WithBulkStrategy(strategy), WithTablePrefix("bulk_"))
+	testAutoSave(t, a)
+	testSaveLoad(t, a)
+	assert.ErrorIs(t, a.AddPolicies("p", "p", [][]string{{"carol", "data3", "read"}, {"alice", "data1", "read"}}), ErrPolicyExists)
+
+	a = initAdapter(t, driverName, dataSourceName, WithBulkStrategy(strategy), WithOnConflict(OnConflictIgnore), WithSaveMode(SaveModeDiff))
+	assert.Nil(t, a.AddPolicies("p", "p", [][]string{{"carol", "data3", "read"}, {"alice", "data1", "read"}}))
+	testBulk(t, a)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}})
+}
+
+func testPages(t *testing.T, driverName string, dataSourceName string) {
+	a := initAdapter(t, driverName, dataSourceName, WithPageSize(2))
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+	sizes := make([]int, 0)
+	rules := make([][]string, 0)
+	assert.Nil(t, a.LoadPolicyPages(func(lines []*ent.CasbinRule) error {
+		sizes = append(sizes, len(lines))
+		for _, line := range lines {
+			rules = append(rules, append([]string{line.Ptype}, CasbinRuleToStringArray(line)...))
+		}
+		return nil
+	}))
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, [][]string{{"p", "alice", "data1", "read"}, {"p", "bob", "data2", "write"}, {"p", "data2_admin", "data2", "read"}, {"p", "data2_admin", "data2", "write"}, {"g", "alice", "data2_admin"}}, rules)
+
+	// An error returned by fn stops the loading.
+	pages := 0
+	errStop := errors.New("stop")
+	assert.ErrorIs(t, a.LoadPolicyPages(func([]*ent.CasbinRule) error {
+		pages++
+		return errStop
+	}), errStop)
+	assert.Equal(t, 1, pages)
+
+	assert.Nil(t, e.LoadFilteredPolicy(Filter{V1: []string{"data2"}}))
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+}
+
+func testParallelLoad(t *testing.T, driverName string, dataSourceName string) {
+	a := initAdapter(t, driverName, dataSourceName, WithParallelLoad(2), WithPageSize(1))
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+	g, err := e.GetGroupingPolicy()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"alice", "data2_admin"}}, g)
+	ok, err := e.Enforce("alice", "data2", "write")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotNil(t, a.LoadPolicyCtx(ctx, e.GetModel()))
+}
+
+func testReplica(t *testing.T, driverName string, dataSourceName string) {
+	primary := openClient(t, driverName, dataSourceName)
+	reads := 0
+	replica := openClient(t, driverName, dataSourceName, ent.Log(func(...any) { reads++ }))
+
+	// Loads go to the replica, writes to the primary.
+	a, err := NewAdapterWithClient(primary, WithReplica(replica.Debug()))
+	assert.Nil(t, err)
+	initPolicy(t, a)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	assert.NotEqual(t, 0, reads)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+	reads = 0
+	_, err = e.AddPolicy("carol", "data3", "read")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, reads)
+	assert.Nil(t, e.LoadFilteredPolicy(Filter{V0: []string{"carol"}}))
+	assert.NotEqual(t, 0, reads)
+	testGetPolicy(t, e, [][]string{{"carol", "data3", "read"}})
+
+	// A replica that cannot be reached fails the loads only, until the
+	// primary is read right after a write.
+	db, err := sql.Open(driverName, dataSourceName)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Close())
+	a, err = NewAdapterWithClient(primary, WithReplicaDB(db), WithReadYourWrites(time.Hour))
+	assert.Nil(t, err)
+	e, _ = casbin.NewEnforcer("examples/rbac_model.conf")
+	e.SetAdapter(a)
+	assert.NotNil(t, e.LoadPolicy())
+	_, err = e.RemovePolicy("carol", "data3", "read")
+	assert.Nil(t, err)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+}
+
+func testTxContext(t *testing.T, driverName string, dataSourceName string) {
+	ctx := context.Background()
+	client := openClient(t, driverName, dataSourceName)
+	defer client.Close()
+	a, err := NewAdapterWithClient(client, WithTablePrefix("tx_"))
+	assert.Nil(t, err)
+	initPolicy(t, a)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+
+	// Writes joining a transaction are discarded when it is rolled back...
+	tx, err := client.Tx(ctx)
+	assert.Nil(t, err)
+	txCtx := NewTxContext(ctx, tx)
+	assert.Nil(t, a.AddPolicyCtx(txCtx, "p", "p", []string{"carol", "data3", "read"}))
+	assert.Nil(t, a.RemovePolicyCtx(txCtx, "p", "p", []string{"alice", "data1", "read"}))
+	assert.Nil(t, tx.Rollback())
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+	// ...and stored when it is committed.
+	tx, err = client.Tx(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, a.AddPoliciesCtx(NewTxContext(ctx, tx), "p", "p", [][]string{{"carol", "data3", "read"}, {"dave", "data4", "write"}}))
+	assert.Nil(t, tx.Commit())
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}, {"dave", "data4", "write"}})
+
+	// The same holds for a *sql.Tx.
+	db, err := sql.Open(driverName, dataSourceName)
+	assert.Nil(t, err)
+	defer db.Close()
+	a, err = NewAdapterWithDB(db, driverName, WithTablePrefix("tx_"))
+	assert.Nil(t, err)
+	sqlTx, err := db.BeginTx(ctx, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, a.RemovePoliciesCtx(NewSQLTxContext(ctx, sqlTx), "p", "p", [][]string{{"carol", "data3", "read"}, {"dave", "data4", "write"}}))
+	assert.Nil(t, sqlTx.Rollback())
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}, {"dave", "data4", "write"}})
+
+	sqlTx, err = db.BeginTx(ctx, nil)
+	assert.Nil(t, err)
+	_, err = a.UpdateFilteredPoliciesCtx(NewSQLTxContext(ctx, sqlTx), "p", "p", [][]string{{"carol", "data3", "write"}}, 0, "carol")
+	assert.Nil(t, err)
+	assert.Nil(t, sqlTx.Commit())
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"dave", "data4", "write"}, {"carol", "data3", "write"}})
+}
+
+func testTransaction(t *testing.T, driverName string, dataSourceName string) {
+	ctx := context.Background()
+	a := initAdapter(t, driverName, dataSourceName, WithTablePrefix("tx_"))
+	e, err := casbin.NewTransactionalEnforcer("examples/rbac_model.conf", a)
+	assert.Nil(t, err)
+	initial := [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}}
+
+	// A rolled back transaction leaves the storage and the model untouched.
+	tx, err := e.BeginTransaction(ctx)
+	assert.Nil(t, err)
+	_, err = tx.AddPolicy("carol", "data3", "read")
+	assert.Nil(t, err)
+	_, err = tx.RemovePolicy("alice", "data1", "read")
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Rollback())
+	testGetPolicy(t, e.Enforcer, initial)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e.Enforcer, initial)
+
+	// So does a transaction that fails to commit, even if some of its
+	// changes were written before the failure.
+	tx, err = e.BeginTransaction(ctx)
+	assert.Nil(t, err)
+	_, err = tx.AddPolicy("carol", "data3", "read")
+	assert.Nil(t, err)
+	_, err = tx.AddNamedPolicy("p", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k")
+	assert.Nil(t, err)
+	assert.True(t, errors.Is(tx.Commit(), ErrTooManyFields))
+	testGetPolicy(t, e.Enforcer, initial)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e.Enforcer, initial)
+
+	// A committed transaction stores all its changes.
+	err = e.WithTransaction(ctx, func(tx *casbin.Transaction) error {
+		if _, err := tx.AddPolicies([][]string{{"carol", "data3", "read"}, {"dave", "data4", "write"}}); err != nil {
+			return err
+		}
+		if _, err := tx.RemovePolicy("alice", "data1", "read"); err != nil {
+			return err
+		}
+		_, err := tx.UpdatePolicy([]string{"bob", "data2", "write"}, []string{"bob", "data2", "read"})
+		return err
+	})
+	assert.Nil(t, err)
+	want := [][]string{{"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}, {"dave", "data4", "write"}, {"bob", "data2", "read"}}
+	policy, err := e.GetPolicy()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, want, policy)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e.Enforcer, want)
+
+	// Read-only adapters cannot begin transactions.
+	a, err = NewAdapter(driverName, dataSourceName, WithTablePrefix("tx_"), WithReadOnly())
+	assert.Nil(t, err)
+	_, err = a.BeginTransaction(ctx)
+	assert.Equal(t, ErrReadOnly, err)
+}
+
+func testNestedTx(t *testing.T, driverName string, dataSourceName string) {
+	ctx := context.Background()
+	a := initAdapter(t, driverName, dataSourceName, WithTablePrefix("tx_"))
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+
+	// Writes within WithTx join its transaction. Failing inner scopes are
+	// rolled back alone, and the transaction carries on.
+	err := a.WithTx(ctx, func(tx *ent.Tx) error {
+		ctx := NewTxContext(ctx, tx)
+		if err := a.AddPoliciesCtx(ctx, "p", "p", [][]string{{"carol", "data3", "read"}}); err != nil {
+			return err
+		}
+		assert.Equal(t, ErrPolicyExists, a.AddPolicyCtx(ctx, "p", "p", []string{"bob", "data2", "write"}))
+		err := a.WithTx(ctx, func(tx *ent.Tx) error {
+			if _, err := tx.CasbinRule.Delete().Exec(ctx); err != nil {
+				return err
+			}
+			return errors.New("inner failure")
+		})
+		assert.EqualError(t, err, "inner failure")
+		return a.RemovePolicyCtx(ctx, "p", "p", []string{"alice", "data1", "read"})
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}})
+
+	// A failing outer transaction rolls back the released inner scopes too.
+	err = a.WithTx(ctx, func(tx *ent.Tx) error {
+		ctx := NewTxContext(ctx, tx)
+		err := a.WithTx(ctx, func(tx *ent.Tx) error {
+			return a.AddPolicyCtx(NewTxContext(ctx, tx), "p", "p", []string{"dave", "data4", "write"})
+		})
+		assert.Nil(t, err)
+		return errors.New("outer failure")
+	})
+	assert.EqualError(t, err, "outer failure")
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}})
+
+	// Adapters joining the same transaction nest their savepoints.
+	b, err := NewAdapter(driverName, dataSourceName, WithTablePrefix("tx_"))
+	assert.Nil(t, err)
+	defer b.Close()
+	err = a.WithTx(ctx, func(tx *ent.Tx) error {
+		ctx := NewTxContext(ctx, tx)
+		err := a.WithTx(ctx, func(tx *ent.Tx) error {
+			if err := b.AddPolicyCtx(NewTxContext(ctx, tx), "p", "p", []string{"erin", "data5", "read"}); err != nil {
+				return err
+			}
+			return errors.New("inner failure")
+		})
+		assert.EqualError(t, err, "inner failure")
+		return b.AddPolicyCtx(ctx, "p", "p", []string{"frank", "data6", "read"})
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}, {"frank", "data6", "read"}})
+	assert.Nil(t, a.RemovePolicy("p", "p", []string{"frank", "data6", "read"}))
+
+	// Writes joining a transaction of the caller use savepoints as well.
+	client := openClient(t, driverName, dataSourceName)
+	defer client.Close()
+	tx, err := client.Tx(ctx)
+	assert.Nil(t, err)
+	txCtx := NewTxContext(ctx, tx)
+	assert.Equal(t, ErrPolicyExists, a.AddPolicyCtx(txCtx, "p", "p", []string{"carol", "data3", "read"}))
+	assert.Nil(t, a.AddPolicyCtx(txCtx, "p", "p", []string{"dave", "data4", "write"}))
+	assert.Nil(t, tx.Commit())
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}, {"dave", "data4", "write"}})
+}
+
+// faultDriver is a driver whose first transactions fail to commit with err.
+type faultDriver struct {
+	dialect.Driver
+	err error
+	// faults is the number of transactions left to fail.
+	faults int
+	// txs is the number of transactions begun.
+	txs int
+}
+
+func (d *faultDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.txs++
+	if d.faults > 0 {
+		d.faults--
+		return &faultTx{Tx: tx, err: d.err}, nil
+	}
+	return tx, nil
+}
+
+type faultTx struct {
+	dialect.Tx
+	err error
+}
+
+func (tx *faultTx) Commit() error {
+	_ = tx.Tx.Rollback()
+	return tx.err
+}
+
+func testRetry(t *testing.T, driverName string, dataSourceName string) {
+	assert.True(t, IsRetryable(fmt.Errorf("committing transaction: %w", &pq.Error{Code: "40001"})))
+	assert.True(t, IsRetryable(&pgconn.PgError{Code: "40P01"}))
+	assert.True(t, IsRetryable(&mysql.MySQLError{Number: 1213}))
+	assert.False(t, IsRetryable(&pq.Error{Code: "23505"}))
+	assert.False(t, IsRetryable(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, IsRetryable(ErrPolicyExists))
+
+	db, err := sql.Open(driverName, dataSourceName)
+	assert.Nil(t, err)
+	defer db.Close()
+	drv := &faultDriver{Driver: openDB(driverName, db), err: &pq.Error{Code: "40001"}}
+	a, err := NewAdapterWithDriver(drv, WithTablePrefix("retry_"), WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}))
+	assert.Nil(t, err)
+	initPolicy(t, a)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+
+	// Transient failures are retried until the transaction succeeds...
+	drv.faults, drv.txs = 2, 0
+	_, err = e.AddPolicy("carol", "data3", "read")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, drv.txs)
+
+	// ...or the attempts run out.
+	drv.faults, drv.txs = 3, 0
+	_, err = e.AddPolicy("dave", "data4", "write")
+	var pqErr *pq.Error
+	assert.True(t, errors.As(err, &pqErr))
+	assert.Equal(t, 3, drv.txs)
+
+	// Other errors are not retried.
+	drv.err = errors.New("permanent failure")
+	drv.faults, drv.txs = 1, 0
+	_, err = e.RemovePolicy("carol", "data3", "read")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, drv.txs)
+
+	drv.faults = 0
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"carol", "data3", "read"}})
+
+	// The rules replaced by a retried update are returned once.
+	drv.err = &pq.Error{Code: "40001"}
+	drv.faults, drv.txs = 1, 0
+	ok, err := e.UpdateFilteredPolicies([][]string{{"bob", "data2", "read"}}, 0, "bob")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, drv.txs)
+	drv.faults, drv.txs = 1, 0
+	replaced, err := a.UpdateFilteredPolicies("p", "p", [][]string{{"bob", "data2", "write"}}, 0, "bob")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"bob", "data2", "read"}}, replaced)
+	assert.Equal(t, 2, drv.txs)
+
+	// A custom classifier decides what is retried.
+	drv.err = errors.New("permanent failure")
+	a, err = NewAdapterWithDriver(drv, WithTablePrefix("retry_"), WithRetry(RetryPolicy{MaxAttempts: 2, Retryable: func(err error) bool {
+		return err != nil && strings.Contains(err.Error(), "permanent")
+	}}))
+	assert.Nil(t, err)
+	drv.faults, drv.txs = 1, 0
+	assert.Nil(t, a.RemovePolicy("p", "p", []string{"carol", "data3", "read"}))
+	assert.Equal(t, 2, drv.txs)
+}
+
+func testSnapshotLoad(t *testing.T, driverName string, dataSourceName string) {
+	removed := []string{"p", "data2_admin", "data2", "write"}
+	for _, snapshot := range []bool{false, true} {
+		options := []Option{WithTablePrefix("snapshot_"), WithPageSize(1)}
+		if snapshot {
+			options = append(options, WithSnapshotLoad())
+		}
+		a := initAdapter(t, driverName, dataSourceName, options...)
+		w, err := NewAdapter(driverName, dataSourceName, WithTablePrefix("snapshot_"))
+		assert.Nil(t, err)
+
+		// A rule is removed while the first page is processed.
+		var loaded [][]string
+		var werr error
+		err = a.LoadPolicyPages(func(lines []*ent.CasbinRule) error {
+			if len(loaded) == 0 {
+				werr = w.RemovePolicy("p", "p", removed[1:])
+			}
+			for _, line := range lines {
+				loaded = append(loaded, append([]string{line.Ptype}, CasbinRuleToStringArray(line)...))
+			}
+			return nil
+		})
+		assert.Nil(t, err)
+		if snapshot {
+			// The later pages still see the rule.
+			assert.Contains(t, loaded, removed)
+			assert.Len(t, loaded, 5)
+		} else {
+			assert.Nil(t, werr)
+			assert.NotContains(t, loaded, removed)
+		}
+		assert.Nil(t, w.Close())
+		assert.Nil(t, a.Close())
+	}
+}
+
+func testNewAdapterWithDB(t *testing.T, driverName string, dataSourceName string) {
+	db, err := sql.Open(driverName, dataSourceName)
+	assert.Nil(t, err)
+	db.SetMaxOpenConns(2)
+
+	_, err = NewAdapterWithDB(db, "oracle")
+	assert.NotNil(t, err)
+	_, err = NewAdapterWithDB(nil, driverName)
+	assert.NotNil(t, err)
+	_, err = NewAdapterWithDriver(nil)
+	assert.NotNil(t, err)
+
+	a, err := NewAdapterWithDB(db, driverName)
+	assert.Nil(t, err)
+	initPolicy(t, a)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+	a, err = NewAdapterWithDriver(entsql.OpenDB(driverName, db), WithTablePrefix("driver_"))
+	assert.Nil(t, err)
+	initPolicy(t, a)
+	e, _ = casbin.NewEnforcer("examples/rbac_model.conf", a)
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+}
+
+func testClose(t *testing.T, driverName string, dataSourceName string) {
+	a, err := NewAdapter(driverName, dataSourceName, WithMaxOpenConns(3), WithMaxIdleConns(1), WithConnMaxLifetime(time.Minute), WithConnMaxIdleTime(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, a.db.Stats().MaxOpenConnections)
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf", a)
+	assert.Nil(t, a.Close())
+	assert.NotNil(t, a.LoadPolicy(e.GetModel()))
+
+	// Clients passed in are left open, and their pools are left alone.
+	client := openClient(t, driverName, dataSourceName)
+	defer client.Close()
+	_, err = NewAdapterWithClient(client, WithMaxOpenConns(3))
+	assert.NotNil(t, err)
+	a, err = NewAdapterWithClient(client, WithTablePrefix("close_"))
+	assert.Nil(t, err)
+	assert.Nil(t, a.Close())
+	assert.Nil(t, a.LoadPolicy(e.GetModel()))
+}
+
+func testMigrate(t *testing.T, driverName string, dataSourceName string) {
+	ctx := context.Background()
+	a, err := NewAdapter(driverName, dataSourceName, WithTableName("casbin_rule_migrate"), WithoutAutoMigrate())
+	assert.Nil(t, err)
+	assert.Nil(t, a.client.Driver().Exec(ctx, "DROP TABLE IF EXISTS casbin_rule_migrate", []any{}, nil))
+
+	// A dry run only reports the planned statements.
+	var buf bytes.Buffer
+	assert.Nil(t, a.MigrateDryRun(ctx, &buf))
+	assert.Contains(t, buf.String(), "casbin_rule_migrate")
+	e, _ := casbin.NewEnforcer("examples/rbac_model.conf")
+	assert.NotNil(t, a.LoadPolicy(e.GetModel()))
+
+	assert.Nil(t, a.Migrate(ctx, migrate.WithDropIndex(true), migrate.WithDropColumn(true)))
+	assert.Nil(t, a.LoadPolicy(e.GetModel()))
+	buf.Reset()
+	assert.Nil(t, a.MigrateDryRun(ctx, &buf))
+	assert.Empty(t, buf.String())
+}
+
+func testManyFields(t *testing.T, a *Adapter) {
+	m, err := model.NewModelFromString(`
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act, f3, f4, f5, f6, f7
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.obj == p.obj && r.act == p.act
+`)
+	assert.Nil(t, err)
+	e, _ := casbin.NewEnforcer(m)
+	e.SetAdapter(a)
+	assert.Nil(t, e.SavePolicy())
+
+	_, err = e.AddPolicy("alice", "data1", "read", "3", "4", "5", "6", "7")
+	assert.Nil(t, err)
+	_, err = e.AddPolicy("bob", "data2", "write", "3", "4", "5", "6", "8")
+	assert.Nil(t, err)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read", "3", "4", "5", "6", "7"}, {"bob", "data2", "write", "3", "4", "5", "6", "8"}})
+
+	// Filters on the fields past V5 only match the intended rules.
+	_, err = e.RemoveFilteredPolicy(7, "8")
+	assert.Nil(t, err)
+	_, err = e.UpdatePolicy([]string{"alice", "data1", "read", "3", "4", "5", "6", "7"}, []string{"alice", "data1", "read", "3", "4", "5", "6", "9"})
+	assert.Nil(t, err)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read", "3", "4", "5", "6", "9"}})
+
+	// Rules that do not fit are rejected instead of being truncated.
+	tooLong := []string{"alice", "data1", "read", "3", "4", "5", "6", "7", "8", "9", "10"}
+	assert.ErrorIs(t, a.AddPolicy("p", "p", tooLong), ErrTooManyFields)
+	assert.ErrorIs(t, a.RemovePolicy("p", "p", tooLong), ErrTooManyFields)
+	assert.ErrorIs(t, a.RemoveFilteredPolicy("p", "p", 10, "10"), ErrTooManyFields)
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "data1", "read", "3", "4", "5", "6", "9"}})
+}
+
+func testEmptyFields(t *testing.T, a *Adapter) {
+	m, err := model.NewModelFromString(`
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`)
+	assert.Nil(t, err)
+	e, _ := casbin.NewEnforcer(m)
+	e.SetAdapter(a)
+	assert.Nil(t, e.SavePolicy())
+
+	// Empty fields, commas and quotes round-trip exactly.
+	rules := [][]string{{"alice", "", "data1", "read"}, {"bob", "dom1", "", ""}, {"carol", "a, b", `"quoted"`, "x"}}
+	_, err = e.AddPolicies(rules)
+	assert.Nil(t, err)
+	e.ClearPolicy()
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, rules)
+
+	old, err := a.UpdateFilteredPolicies("p", "p", [][]string{{"bob", "dom2", "", ""}}, 0, "bob")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"bob", "dom1", "", ""}}, old)
+
+	// Rules that only differ by a trailing empty field are removed and updated on their own.
+	assert.Nil(t, a.AddPolicies("p", "p", [][]string{{"erin", "dom1"}, {"erin", "dom1", ""}}))
+	assert.Nil(t, a.UpdatePolicy("p", "p", []string{"erin", "dom1"}, []string{"erin", "dom2"}))
+	assert.Nil(t, a.RemovePolicy("p", "p", []string{"erin", "dom2"}))
+	assert.Nil(t, a.RemovePolicies("p", "p", [][]string{{"erin", "dom1"}}))
+	lines, err := a.client.CasbinRule.Query().Where(casbinrule.V0EQ("erin")).All(context.Background())
+	assert.Nil(t, err)
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, []string{"erin", "dom1", ""}, CasbinRuleToStringArray(lines[0]))
+	}
+	assert.Nil(t, a.RemovePolicy("p", "p", []string{"erin", "dom1", ""}))
+
+	// Rules of assertions the model does not define are skipped.
+	assert.Nil(t, a.AddPolicy("g", "g9", []string{"alice", "admin"}))
+	e.ClearPolicy()
+	assert.Nil(t, e.LoadPolicy())
+	testGetPolicy(t, e, [][]string{{"alice", "", "data1", "read"}, {"carol", "a, b", `"quoted"`, "x"}, {"bob", "dom2", "", ""}})
+	assert.Nil(t, a.RemovePolicy("g", "g9", []string{"alice", "admin"}))
+
+	// Rules stored without arity by older versions lose their trailing empty fields only.
+	drv := a.client.Driver()
+	query, args := entsql.Dialect(drv.Dialect()).Insert(a.tableName).
+		Columns(casbinrule.FieldPtype, casbinrule.FieldV0, casbinrule.FieldV1, casbinrule.FieldV2, casbinrule.FieldV3).
+		Values("p", "dave", "", "data2", "write").
+		Query()
+	assert.Nil(t, drv.Exec(context.Background(), query, args, nil))
+	rule, err := a.client.CasbinRule.Query().Where(casbinrule.V0EQ("dave")).Only(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, rule.Arity)
+	assert.Equal(t, []string{"dave", "", "data2", "write"}, CasbinRuleToStringArray(rule))
 }
 
 func TestAdapters(t *testing.T) {
@@ -293,6 +1308,7 @@ func TestAdapters(t *testing.T) {
 	a = initAdapterWithClientInstance(t, db)
 	testAutoSave(t, a)
 	testSaveLoad(t, a)
+	testRemoveDuplicates(t, db)
 
 	db, err = ent.Open("postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
 	if err != nil {
@@ -301,6 +1317,65 @@ func TestAdapters(t *testing.T) {
 	a = initAdapterWithClientInstance(t, db)
 	testAutoSave(t, a)
 	testSaveLoad(t, a)
+	testRemoveDuplicates(t, db)
+
+	a = initAdapter(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testContext(t, a)
+	testTableName(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testOnConflict(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testLongRules(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testOptions(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testSaveMode(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testBulk(t, a)
+	testPages(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testParallelLoad(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testReplica(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testNewAdapterWithDB(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testTxContext(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testTransaction(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testNestedTx(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testRetry(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testSnapshotLoad(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testClose(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testBulkStrategy(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin", BulkStrategyInsert)
+	testMigrate(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testManyFields(t, a)
+	testEmptyFields(t, a)
+
+	a = initAdapter(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
+	testFilteredPolicy(t, a)
+	testIncrementalFilteredPolicy(t, a)
+	testFilterExpr(t, a)
+
+	a = initAdapter(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testContext(t, a)
+	testTableName(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testOnConflict(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testLongRules(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testOptions(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testSaveMode(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testBulk(t, a)
+	testPages(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testParallelLoad(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testReplica(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testNewAdapterWithDB(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testNewAdapterWithDB(t, "pgx", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testTxContext(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testTransaction(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testNestedTx(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testRetry(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testSnapshotLoad(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testClose(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testBulkStrategy(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin", BulkStrategyInsert)
+	testBulkStrategy(t, "pgx", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin", BulkStrategyCopy)
+	testMigrate(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testManyFields(t, a)
+	testEmptyFields(t, a)
+
+	a = initAdapter(t, "postgres", "user=postgres password=postgres host=127.0.0.1 port=5432 sslmode=disable dbname=casbin")
+	testFilteredPolicy(t, a)
+	testIncrementalFilteredPolicy(t, a)
+	testFilterExpr(t, a)
 
 	a = initAdapter(t, "mysql", "root:@tcp(127.0.0.1:3306)/casbin")
 	testUpdatePolicy(t, a)
@@ -312,3 +1387,109 @@ func TestAdapters(t *testing.T) {
 	testUpdatePolicies(t, a)
 	testUpdateFilteredPolicies(t, a)
 }
+
+func benchmarkRemovePolicies(b *testing.B, driverName string, dataSourceName string, remove func(a *Adapter, rules [][]string) error) {
+	a, err := NewAdapter(driverName, dataSourceName, WithTableName("casbin_rule_bench"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	rules := make([][]string, 0, 1000)
+	for i := 0; i < cap(rules); i++ {
+		rules = append(rules, []string{fmt.Sprintf("user%d", i), "data", "read"})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		if err := a.AddPolicies("p", "p", rules); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+		if err := remove(a, rules); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// removePoliciesPerRule removes rules with one statement per rule,
+// as RemovePolicies did before deletes were batched.
+func removePoliciesPerRule(a *Adapter, rules [][]string) error {
+	return a.WithTx(context.Background(), func(tx *ent.Tx) error {
+		for _, rule := range rules {
+			cond, err := a.rulePredicates("p", rule)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.CasbinRule.Delete().Where(cond...).Exec(context.Background()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func BenchmarkRemovePolicies(b *testing.B) {
+	b.Run("PerRule", func(b *testing.B) {
+		benchmarkRemovePolicies(b, "mysql", "root:@tcp(127.0.0.1:3306)/casbin", removePoliciesPerRule)
+	})
+	b.Run("Batched", func(b *testing.B) {
+		benchmarkRemovePolicies(b, "mysql", "root:@tcp(127.0.0.1:3306)/casbin", func(a *Adapter, rules [][]string) error {
+			return a.RemovePolicies("p", "p", rules)
+		})
+	})
+}
+
+func TestSQLiteAdapter(t *testing.T) {
+	// A named in-memory database is shared by all connections of the process.
+	dsn := "file:casbin?mode=memory&cache=shared&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	a := initAdapter(t, "sqlite", dsn)
+	testAutoSave(t, a)
+	testSaveLoad(t, a)
+
+	a = initAdapterWithClientInstance(t, openClient(t, "sqlite", dsn))
+	testAutoSave(t, a)
+	testSaveLoad(t, a)
+	testRemoveDuplicates(t, openClient(t, "sqlite", dsn))
+
+	a = initAdapter(t, "sqlite", dsn)
+	testContext(t, a)
+	testTableName(t, "sqlite", dsn)
+	testOnConflict(t, "sqlite", dsn)
+	testLongRules(t, "sqlite", dsn)
+	testOptions(t, "sqlite", dsn)
+	testSaveMode(t, "sqlite", dsn)
+	testBulk(t, a)
+	testPages(t, "sqlite", dsn)
+	testParallelLoad(t, "sqlite", dsn)
+	testReplica(t, "sqlite", dsn)
+	testNewAdapterWithDB(t, "sqlite", dsn)
+	testTxContext(t, "sqlite", dsn)
+	testTransaction(t, "sqlite", dsn)
+	testNestedTx(t, "sqlite", dsn)
+	testRetry(t, "sqlite", dsn)
+	// Unlike one in WAL mode, a shared-cache database blocks writes during reads.
+	testSnapshotLoad(t, "sqlite", filepath.Join(t.TempDir(), "casbin.db")+"?_pragma=journal_mode(WAL)")
+	testClose(t, "sqlite", dsn)
+	// The default table must not exist yet.
+	testLegacyTable(t, "sqlite", "file:"+filepath.Join(t.TempDir(), "legacy.db"))
+	testBulkStrategy(t, "sqlite", dsn, BulkStrategyInsert)
+	testMigrate(t, "sqlite", dsn)
+	testManyFields(t, a)
+	testEmptyFields(t, a)
+
+	a = initAdapter(t, "sqlite", dsn)
+	testFilteredPolicy(t, a)
+	testIncrementalFilteredPolicy(t, a)
+	testFilterExpr(t, a)
+
+	a = initAdapter(t, "sqlite", dsn)
+	testUpdatePolicy(t, a)
+	testUpdatePolicies(t, a)
+	testUpdateFilteredPolicies(t, a)
+
+	// "sqlite3" uses the built-in driver, with the pragmas the migration needs.
+	a = initAdapter(t, "sqlite3", ":memory:")
+	testAutoSave(t, a)
+	testSaveLoad(t, a)
+	assert.Nil(t, a.Close())
+}
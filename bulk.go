@@ -0,0 +1,180 @@
+// Copyright 2021 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entadapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/casbin/ent-adapter/ent"
+	"github.com/casbin/ent-adapter/ent/casbinrule"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// copyConnKey is the context key of the connection a transaction
+// of an adapter using BulkStrategyCopy runs on.
+type copyConnKey struct{}
+
+// connDriver is a dialect.Driver that runs everything on a single connection.
+type connDriver struct {
+	entsql.Conn
+	conn    *sql.Conn
+	dialect string
+}
+
+// newConnDriver returns a driver that runs everything on conn.
+func newConnDriver(dialect string, conn *sql.Conn) *connDriver {
+	return &connDriver{Conn: entsql.Conn{ExecQuerier: conn}, conn: conn, dialect: dialect}
+}
+
+// Dialect implements the dialect.Driver interface.
+func (d *connDriver) Dialect() string {
+	return d.dialect
+}
+
+// Tx starts a transaction on the connection.
+func (d *connDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.BeginTx(ctx, nil)
+}
+
+// BeginTx starts a transaction with options on the connection.
+func (d *connDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	tx, err := d.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &entsql.Tx{Conn: entsql.Conn{ExecQuerier: tx}, Tx: tx}, nil
+}
+
+// Close releases the connection.
+func (d *connDriver) Close() error {
+	return d.conn.Close()
+}
+
+// sqlDB returns the database drv runs on, or nil if it cannot be determined.
+func sqlDB(drv dialect.Driver) *sql.DB {
+	for {
+		switch d := drv.(type) {
+		case *dialect.DebugDriver:
+			drv = d.Driver
+		case *tableDriver:
+			drv = d.Driver
+		case *entsql.Driver:
+			db, _ := d.ExecQuerier.(*sql.DB)
+			return db
+		default:
+			return nil
+		}
+	}
+}
+
+// checkBulkStrategy returns an error if the configured bulk strategy
+// is not supported by the database.
+func (a *Adapter) checkBulkStrategy() error {
+	switch a.bulkStrategy {
+	case BulkStrategyCopy:
+		if a.db == nil {
+			return fmt.Errorf("bulk strategy COPY requires a client opened on a *sql.DB")
+		}
+		if _, ok := a.db.Driver().(*stdlib.Driver); !ok {
+			return fmt.Errorf("bulk strategy COPY requires the pgx driver, got %T", a.db.Driver())
+		}
+	}
+	return nil
+}
+
+// insertValues returns the values of the insert columns of line,
+// in the order of ruleColumns followed by the arity and the rule hash.
+func insertValues(line *ent.CasbinRuleCreate) []any {
+	m := line.Mutation()
+	values := make([]any, 0, insertColumns)
+	for _, column := range ruleColumns {
+		v, ok := m.Field(column)
+		if !ok {
+			v = ""
+		}
+		values = append(values, v)
+	}
+	arity, _ := m.Arity()
+	hash, _ := m.RuleHash()
+	return append(values, arity, hash)
+}
+
+// insertColumnNames returns the names of the insert columns.
+func insertColumnNames() []string {
+	return append(append([]string{}, ruleColumns...), casbinrule.FieldArity, casbinrule.FieldRuleHash)
+}
+
+// copyInsert copies lines into a temporary table over the pgx connection
+// of conn and merges them into the policy table, resolving rules that are
+// already stored according to the configured OnConflict mode.
+func (a *Adapter) copyInsert(ctx context.Context, conn *sql.Conn, lines []*ent.CasbinRuleCreate) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	rows := make([][]any, 0, len(lines))
+	for _, line := range lines {
+		rows = append(rows, insertValues(line))
+	}
+	columns := insertColumnNames()
+	table := pgx.Identifier{a.tableName}.Sanitize()
+	temp := pgx.Identifier{a.tableName + "_copy"}
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, pgx.Identifier{column}.Sanitize())
+	}
+	columnList := strings.Join(names, ", ")
+
+	merge := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", table, columnList, columnList, temp.Sanitize())
+	// The rule hash is the last column and the conflict target.
+	hash := names[len(names)-1]
+	switch a.onConflict {
+	case OnConflictIgnore:
+		merge += fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", hash)
+	case OnConflictUpsert:
+		merge += fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s = EXCLUDED.%s",
+			hash, names[len(ruleColumns)], names[len(ruleColumns)])
+	}
+
+	err := conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("bulk strategy COPY requires the pgx driver, got %T", driverConn)
+		}
+		pc := c.Conn()
+		create := fmt.Sprintf("CREATE TEMPORARY TABLE %s ON COMMIT DROP AS SELECT %s FROM %s WITH NO DATA", temp.Sanitize(), columnList, table)
+		if _, err := pc.Exec(ctx, create); err != nil {
+			return err
+		}
+		if _, err := pc.CopyFrom(ctx, temp, columns, pgx.CopyFromRows(rows)); err != nil {
+			return err
+		}
+		if _, err := pc.Exec(ctx, merge); err != nil {
+			return err
+		}
+		_, err := pc.Exec(ctx, "DROP TABLE "+temp.Sanitize())
+		return err
+	})
+	if sqlgraph.IsUniqueConstraintError(err) {
+		return ErrPolicyExists
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entadapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"github.com/casbin/ent-adapter/ent/casbinrule"
+)
+
+// tableDriver is a dialect.Driver that points every statement generated for
+// the casbinrule table at another table. Ent always quotes identifiers, so the
+// quoted generated table name is replaced by the quoted configured one.
+type tableDriver struct {
+	dialect.Driver
+	replacer *strings.Replacer
+}
+
+// newTableDriver wraps drv so that statements use table instead of the
+// generated casbinrule table. drv is returned as is if no rewrite is needed.
+func newTableDriver(drv dialect.Driver, table string) dialect.Driver {
+	if table == casbinrule.Table {
+		return drv
+	}
+	return &tableDriver{
+		Driver: drv,
+		replacer: strings.NewReplacer(
+			"`"+casbinrule.Table+"`", "`"+table+"`",
+			`"`+casbinrule.Table+`"`, `"`+table+`"`,
+		),
+	}
+}
+
+// Exec implements the dialect.Execer interface.
+func (d *tableDriver) Exec(ctx context.Context, query string, args, v any) error {
+	return d.Driver.Exec(ctx, d.replacer.Replace(query), args, v)
+}
+
+// Query implements the dialect.Querier interface.
+func (d *tableDriver) Query(ctx context.Context, query string, args, v any) error {
+	return d.Driver.Query(ctx, d.replacer.Replace(query), args, v)
+}
+
+// Tx starts a transaction whose statements are rewritten as well.
+func (d *tableDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tableTx{Tx: tx, replacer: d.replacer}, nil
+}
+
+// BeginTx starts a transaction with options whose statements are rewritten as well.
+func (d *tableDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("driver %T does not support transaction options", d.Driver)
+	}
+	tx, err := drv.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tableTx{Tx: tx, replacer: d.replacer}, nil
+}
+
+// tableTx is the dialect.Tx counterpart of tableDriver.
+type tableTx struct {
+	dialect.Tx
+	replacer *strings.Replacer
+}
+
+// Exec implements the dialect.Execer interface.
+func (tx *tableTx) Exec(ctx context.Context, query string, args, v any) error {
+	return tx.Tx.Exec(ctx, tx.replacer.Replace(query), args, v)
+}
+
+// Query implements the dialect.Querier interface.
+func (tx *tableTx) Query(ctx context.Context, query string, args, v any) error {
+	return tx.Tx.Query(ctx, tx.replacer.Replace(query), args, v)
+}
@@ -29,7 +29,19 @@ type CasbinRule struct {
 	// V4 holds the value of the "V4" field.
 	V4 string `json:"V4,omitempty"`
 	// V5 holds the value of the "V5" field.
-	V5           string `json:"V5,omitempty"`
+	V5 string `json:"V5,omitempty"`
+	// V6 holds the value of the "V6" field.
+	V6 string `json:"V6,omitempty"`
+	// V7 holds the value of the "V7" field.
+	V7 string `json:"V7,omitempty"`
+	// V8 holds the value of the "V8" field.
+	V8 string `json:"V8,omitempty"`
+	// V9 holds the value of the "V9" field.
+	V9 string `json:"V9,omitempty"`
+	// Arity holds the value of the "Arity" field.
+	Arity *int `json:"Arity,omitempty"`
+	// RuleHash holds the value of the "RuleHash" field.
+	RuleHash     *string `json:"RuleHash,omitempty"`
 	selectValues sql.SelectValues
 }
 
@@ -38,9 +50,9 @@ func (*CasbinRule) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case casbinrule.FieldID:
+		case casbinrule.FieldID, casbinrule.FieldArity:
 			values[i] = new(sql.NullInt64)
-		case casbinrule.FieldPtype, casbinrule.FieldV0, casbinrule.FieldV1, casbinrule.FieldV2, casbinrule.FieldV3, casbinrule.FieldV4, casbinrule.FieldV5:
+		case casbinrule.FieldPtype, casbinrule.FieldV0, casbinrule.FieldV1, casbinrule.FieldV2, casbinrule.FieldV3, casbinrule.FieldV4, casbinrule.FieldV5, casbinrule.FieldV6, casbinrule.FieldV7, casbinrule.FieldV8, casbinrule.FieldV9, casbinrule.FieldRuleHash:
 			values[i] = new(sql.NullString)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -105,6 +117,44 @@ func (_m *CasbinRule) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.V5 = value.String
 			}
+		case casbinrule.FieldV6:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field V6", values[i])
+			} else if value.Valid {
+				_m.V6 = value.String
+			}
+		case casbinrule.FieldV7:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field V7", values[i])
+			} else if value.Valid {
+				_m.V7 = value.String
+			}
+		case casbinrule.FieldV8:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field V8", values[i])
+			} else if value.Valid {
+				_m.V8 = value.String
+			}
+		case casbinrule.FieldV9:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field V9", values[i])
+			} else if value.Valid {
+				_m.V9 = value.String
+			}
+		case casbinrule.FieldArity:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field Arity", values[i])
+			} else if value.Valid {
+				_m.Arity = new(int)
+				*_m.Arity = int(value.Int64)
+			}
+		case casbinrule.FieldRuleHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field RuleHash", values[i])
+			} else if value.Valid {
+				_m.RuleHash = new(string)
+				*_m.RuleHash = value.String
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -161,6 +211,28 @@ func (_m *CasbinRule) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("V5=")
 	builder.WriteString(_m.V5)
+	builder.WriteString(", ")
+	builder.WriteString("V6=")
+	builder.WriteString(_m.V6)
+	builder.WriteString(", ")
+	builder.WriteString("V7=")
+	builder.WriteString(_m.V7)
+	builder.WriteString(", ")
+	builder.WriteString("V8=")
+	builder.WriteString(_m.V8)
+	builder.WriteString(", ")
+	builder.WriteString("V9=")
+	builder.WriteString(_m.V9)
+	builder.WriteString(", ")
+	if v := _m.Arity; v != nil {
+		builder.WriteString("Arity=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.RuleHash; v != nil {
+		builder.WriteString("RuleHash=")
+		builder.WriteString(*v)
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -25,8 +25,20 @@ const (
 	FieldV4 = "v4"
 	// FieldV5 holds the string denoting the v5 field in the database.
 	FieldV5 = "v5"
+	// FieldV6 holds the string denoting the v6 field in the database.
+	FieldV6 = "v6"
+	// FieldV7 holds the string denoting the v7 field in the database.
+	FieldV7 = "v7"
+	// FieldV8 holds the string denoting the v8 field in the database.
+	FieldV8 = "v8"
+	// FieldV9 holds the string denoting the v9 field in the database.
+	FieldV9 = "v9"
+	// FieldArity holds the string denoting the arity field in the database.
+	FieldArity = "arity"
+	// FieldRuleHash holds the string denoting the rulehash field in the database.
+	FieldRuleHash = "rule_hash"
 	// Table holds the table name of the casbinrule in the database.
-	Table = "casbin_rules"
+	Table = "casbin_rule"
 )
 
 // Columns holds all SQL columns for casbinrule fields.
@@ -39,6 +51,12 @@ var Columns = []string{
 	FieldV3,
 	FieldV4,
 	FieldV5,
+	FieldV6,
+	FieldV7,
+	FieldV8,
+	FieldV9,
+	FieldArity,
+	FieldRuleHash,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -66,6 +84,16 @@ var (
 	DefaultV4 string
 	// DefaultV5 holds the default value on creation for the "V5" field.
 	DefaultV5 string
+	// DefaultV6 holds the default value on creation for the "V6" field.
+	DefaultV6 string
+	// DefaultV7 holds the default value on creation for the "V7" field.
+	DefaultV7 string
+	// DefaultV8 holds the default value on creation for the "V8" field.
+	DefaultV8 string
+	// DefaultV9 holds the default value on creation for the "V9" field.
+	DefaultV9 string
+	// RuleHashValidator is a validator for the "RuleHash" field. It is called by the builders before save.
+	RuleHashValidator func(string) error
 )
 
 // OrderOption defines the ordering options for the CasbinRule queries.
@@ -110,3 +138,33 @@ func ByV4(opts ...sql.OrderTermOption) OrderOption {
 func ByV5(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldV5, opts...).ToFunc()
 }
+
+// ByV6 orders the results by the V6 field.
+func ByV6(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldV6, opts...).ToFunc()
+}
+
+// ByV7 orders the results by the V7 field.
+func ByV7(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldV7, opts...).ToFunc()
+}
+
+// ByV8 orders the results by the V8 field.
+func ByV8(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldV8, opts...).ToFunc()
+}
+
+// ByV9 orders the results by the V9 field.
+func ByV9(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldV9, opts...).ToFunc()
+}
+
+// ByArity orders the results by the Arity field.
+func ByArity(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldArity, opts...).ToFunc()
+}
+
+// ByRuleHash orders the results by the RuleHash field.
+func ByRuleHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRuleHash, opts...).ToFunc()
+}
@@ -87,6 +87,36 @@ func V5(v string) predicate.CasbinRule {
 	return predicate.CasbinRule(sql.FieldEQ(FieldV5, v))
 }
 
+// V6 applies equality check predicate on the "V6" field. It's identical to V6EQ.
+func V6(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV6, v))
+}
+
+// V7 applies equality check predicate on the "V7" field. It's identical to V7EQ.
+func V7(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV7, v))
+}
+
+// V8 applies equality check predicate on the "V8" field. It's identical to V8EQ.
+func V8(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV8, v))
+}
+
+// V9 applies equality check predicate on the "V9" field. It's identical to V9EQ.
+func V9(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV9, v))
+}
+
+// Arity applies equality check predicate on the "Arity" field. It's identical to ArityEQ.
+func Arity(v int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldArity, v))
+}
+
+// RuleHash applies equality check predicate on the "RuleHash" field. It's identical to RuleHashEQ.
+func RuleHash(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldRuleHash, v))
+}
+
 // PtypeEQ applies the EQ predicate on the "Ptype" field.
 func PtypeEQ(v string) predicate.CasbinRule {
 	return predicate.CasbinRule(sql.FieldEQ(FieldPtype, v))
@@ -542,6 +572,391 @@ func V5ContainsFold(v string) predicate.CasbinRule {
 	return predicate.CasbinRule(sql.FieldContainsFold(FieldV5, v))
 }
 
+// V6EQ applies the EQ predicate on the "V6" field.
+func V6EQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV6, v))
+}
+
+// V6NEQ applies the NEQ predicate on the "V6" field.
+func V6NEQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNEQ(FieldV6, v))
+}
+
+// V6In applies the In predicate on the "V6" field.
+func V6In(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIn(FieldV6, vs...))
+}
+
+// V6NotIn applies the NotIn predicate on the "V6" field.
+func V6NotIn(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotIn(FieldV6, vs...))
+}
+
+// V6GT applies the GT predicate on the "V6" field.
+func V6GT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGT(FieldV6, v))
+}
+
+// V6GTE applies the GTE predicate on the "V6" field.
+func V6GTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGTE(FieldV6, v))
+}
+
+// V6LT applies the LT predicate on the "V6" field.
+func V6LT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLT(FieldV6, v))
+}
+
+// V6LTE applies the LTE predicate on the "V6" field.
+func V6LTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLTE(FieldV6, v))
+}
+
+// V6Contains applies the Contains predicate on the "V6" field.
+func V6Contains(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContains(FieldV6, v))
+}
+
+// V6HasPrefix applies the HasPrefix predicate on the "V6" field.
+func V6HasPrefix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasPrefix(FieldV6, v))
+}
+
+// V6HasSuffix applies the HasSuffix predicate on the "V6" field.
+func V6HasSuffix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasSuffix(FieldV6, v))
+}
+
+// V6EqualFold applies the EqualFold predicate on the "V6" field.
+func V6EqualFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEqualFold(FieldV6, v))
+}
+
+// V6ContainsFold applies the ContainsFold predicate on the "V6" field.
+func V6ContainsFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContainsFold(FieldV6, v))
+}
+
+// V7EQ applies the EQ predicate on the "V7" field.
+func V7EQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV7, v))
+}
+
+// V7NEQ applies the NEQ predicate on the "V7" field.
+func V7NEQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNEQ(FieldV7, v))
+}
+
+// V7In applies the In predicate on the "V7" field.
+func V7In(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIn(FieldV7, vs...))
+}
+
+// V7NotIn applies the NotIn predicate on the "V7" field.
+func V7NotIn(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotIn(FieldV7, vs...))
+}
+
+// V7GT applies the GT predicate on the "V7" field.
+func V7GT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGT(FieldV7, v))
+}
+
+// V7GTE applies the GTE predicate on the "V7" field.
+func V7GTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGTE(FieldV7, v))
+}
+
+// V7LT applies the LT predicate on the "V7" field.
+func V7LT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLT(FieldV7, v))
+}
+
+// V7LTE applies the LTE predicate on the "V7" field.
+func V7LTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLTE(FieldV7, v))
+}
+
+// V7Contains applies the Contains predicate on the "V7" field.
+func V7Contains(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContains(FieldV7, v))
+}
+
+// V7HasPrefix applies the HasPrefix predicate on the "V7" field.
+func V7HasPrefix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasPrefix(FieldV7, v))
+}
+
+// V7HasSuffix applies the HasSuffix predicate on the "V7" field.
+func V7HasSuffix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasSuffix(FieldV7, v))
+}
+
+// V7EqualFold applies the EqualFold predicate on the "V7" field.
+func V7EqualFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEqualFold(FieldV7, v))
+}
+
+// V7ContainsFold applies the ContainsFold predicate on the "V7" field.
+func V7ContainsFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContainsFold(FieldV7, v))
+}
+
+// V8EQ applies the EQ predicate on the "V8" field.
+func V8EQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV8, v))
+}
+
+// V8NEQ applies the NEQ predicate on the "V8" field.
+func V8NEQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNEQ(FieldV8, v))
+}
+
+// V8In applies the In predicate on the "V8" field.
+func V8In(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIn(FieldV8, vs...))
+}
+
+// V8NotIn applies the NotIn predicate on the "V8" field.
+func V8NotIn(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotIn(FieldV8, vs...))
+}
+
+// V8GT applies the GT predicate on the "V8" field.
+func V8GT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGT(FieldV8, v))
+}
+
+// V8GTE applies the GTE predicate on the "V8" field.
+func V8GTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGTE(FieldV8, v))
+}
+
+// V8LT applies the LT predicate on the "V8" field.
+func V8LT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLT(FieldV8, v))
+}
+
+// V8LTE applies the LTE predicate on the "V8" field.
+func V8LTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLTE(FieldV8, v))
+}
+
+// V8Contains applies the Contains predicate on the "V8" field.
+func V8Contains(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContains(FieldV8, v))
+}
+
+// V8HasPrefix applies the HasPrefix predicate on the "V8" field.
+func V8HasPrefix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasPrefix(FieldV8, v))
+}
+
+// V8HasSuffix applies the HasSuffix predicate on the "V8" field.
+func V8HasSuffix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasSuffix(FieldV8, v))
+}
+
+// V8EqualFold applies the EqualFold predicate on the "V8" field.
+func V8EqualFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEqualFold(FieldV8, v))
+}
+
+// V8ContainsFold applies the ContainsFold predicate on the "V8" field.
+func V8ContainsFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContainsFold(FieldV8, v))
+}
+
+// V9EQ applies the EQ predicate on the "V9" field.
+func V9EQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldV9, v))
+}
+
+// V9NEQ applies the NEQ predicate on the "V9" field.
+func V9NEQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNEQ(FieldV9, v))
+}
+
+// V9In applies the In predicate on the "V9" field.
+func V9In(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIn(FieldV9, vs...))
+}
+
+// V9NotIn applies the NotIn predicate on the "V9" field.
+func V9NotIn(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotIn(FieldV9, vs...))
+}
+
+// V9GT applies the GT predicate on the "V9" field.
+func V9GT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGT(FieldV9, v))
+}
+
+// V9GTE applies the GTE predicate on the "V9" field.
+func V9GTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGTE(FieldV9, v))
+}
+
+// V9LT applies the LT predicate on the "V9" field.
+func V9LT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLT(FieldV9, v))
+}
+
+// V9LTE applies the LTE predicate on the "V9" field.
+func V9LTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLTE(FieldV9, v))
+}
+
+// V9Contains applies the Contains predicate on the "V9" field.
+func V9Contains(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContains(FieldV9, v))
+}
+
+// V9HasPrefix applies the HasPrefix predicate on the "V9" field.
+func V9HasPrefix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasPrefix(FieldV9, v))
+}
+
+// V9HasSuffix applies the HasSuffix predicate on the "V9" field.
+func V9HasSuffix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasSuffix(FieldV9, v))
+}
+
+// V9EqualFold applies the EqualFold predicate on the "V9" field.
+func V9EqualFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEqualFold(FieldV9, v))
+}
+
+// V9ContainsFold applies the ContainsFold predicate on the "V9" field.
+func V9ContainsFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContainsFold(FieldV9, v))
+}
+
+// ArityEQ applies the EQ predicate on the "Arity" field.
+func ArityEQ(v int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldArity, v))
+}
+
+// ArityNEQ applies the NEQ predicate on the "Arity" field.
+func ArityNEQ(v int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNEQ(FieldArity, v))
+}
+
+// ArityIn applies the In predicate on the "Arity" field.
+func ArityIn(vs ...int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIn(FieldArity, vs...))
+}
+
+// ArityNotIn applies the NotIn predicate on the "Arity" field.
+func ArityNotIn(vs ...int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotIn(FieldArity, vs...))
+}
+
+// ArityGT applies the GT predicate on the "Arity" field.
+func ArityGT(v int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGT(FieldArity, v))
+}
+
+// ArityGTE applies the GTE predicate on the "Arity" field.
+func ArityGTE(v int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGTE(FieldArity, v))
+}
+
+// ArityLT applies the LT predicate on the "Arity" field.
+func ArityLT(v int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLT(FieldArity, v))
+}
+
+// ArityLTE applies the LTE predicate on the "Arity" field.
+func ArityLTE(v int) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLTE(FieldArity, v))
+}
+
+// ArityIsNil applies the IsNil predicate on the "Arity" field.
+func ArityIsNil() predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIsNull(FieldArity))
+}
+
+// ArityNotNil applies the NotNil predicate on the "Arity" field.
+func ArityNotNil() predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotNull(FieldArity))
+}
+
+// RuleHashEQ applies the EQ predicate on the "RuleHash" field.
+func RuleHashEQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEQ(FieldRuleHash, v))
+}
+
+// RuleHashNEQ applies the NEQ predicate on the "RuleHash" field.
+func RuleHashNEQ(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNEQ(FieldRuleHash, v))
+}
+
+// RuleHashIn applies the In predicate on the "RuleHash" field.
+func RuleHashIn(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIn(FieldRuleHash, vs...))
+}
+
+// RuleHashNotIn applies the NotIn predicate on the "RuleHash" field.
+func RuleHashNotIn(vs ...string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotIn(FieldRuleHash, vs...))
+}
+
+// RuleHashGT applies the GT predicate on the "RuleHash" field.
+func RuleHashGT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGT(FieldRuleHash, v))
+}
+
+// RuleHashGTE applies the GTE predicate on the "RuleHash" field.
+func RuleHashGTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldGTE(FieldRuleHash, v))
+}
+
+// RuleHashLT applies the LT predicate on the "RuleHash" field.
+func RuleHashLT(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLT(FieldRuleHash, v))
+}
+
+// RuleHashLTE applies the LTE predicate on the "RuleHash" field.
+func RuleHashLTE(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldLTE(FieldRuleHash, v))
+}
+
+// RuleHashContains applies the Contains predicate on the "RuleHash" field.
+func RuleHashContains(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContains(FieldRuleHash, v))
+}
+
+// RuleHashHasPrefix applies the HasPrefix predicate on the "RuleHash" field.
+func RuleHashHasPrefix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasPrefix(FieldRuleHash, v))
+}
+
+// RuleHashHasSuffix applies the HasSuffix predicate on the "RuleHash" field.
+func RuleHashHasSuffix(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldHasSuffix(FieldRuleHash, v))
+}
+
+// RuleHashIsNil applies the IsNil predicate on the "RuleHash" field.
+func RuleHashIsNil() predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldIsNull(FieldRuleHash))
+}
+
+// RuleHashNotNil applies the NotNil predicate on the "RuleHash" field.
+func RuleHashNotNil() predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldNotNull(FieldRuleHash))
+}
+
+// RuleHashEqualFold applies the EqualFold predicate on the "RuleHash" field.
+func RuleHashEqualFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldEqualFold(FieldRuleHash, v))
+}
+
+// RuleHashContainsFold applies the ContainsFold predicate on the "RuleHash" field.
+func RuleHashContainsFold(v string) predicate.CasbinRule {
+	return predicate.CasbinRule(sql.FieldContainsFold(FieldRuleHash, v))
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.CasbinRule) predicate.CasbinRule {
 	return predicate.CasbinRule(sql.AndPredicates(predicates...))
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
 	"github.com/casbin/ent-adapter/ent/casbinrule"
@@ -17,6 +18,7 @@ type CasbinRuleCreate struct {
 	config
 	mutation *CasbinRuleMutation
 	hooks    []Hook
+	conflict []sql.ConflictOption
 }
 
 // SetPtype sets the "Ptype" field.
@@ -117,6 +119,90 @@ func (_c *CasbinRuleCreate) SetNillableV5(v *string) *CasbinRuleCreate {
 	return _c
 }
 
+// SetV6 sets the "V6" field.
+func (_c *CasbinRuleCreate) SetV6(v string) *CasbinRuleCreate {
+	_c.mutation.SetV6(v)
+	return _c
+}
+
+// SetNillableV6 sets the "V6" field if the given value is not nil.
+func (_c *CasbinRuleCreate) SetNillableV6(v *string) *CasbinRuleCreate {
+	if v != nil {
+		_c.SetV6(*v)
+	}
+	return _c
+}
+
+// SetV7 sets the "V7" field.
+func (_c *CasbinRuleCreate) SetV7(v string) *CasbinRuleCreate {
+	_c.mutation.SetV7(v)
+	return _c
+}
+
+// SetNillableV7 sets the "V7" field if the given value is not nil.
+func (_c *CasbinRuleCreate) SetNillableV7(v *string) *CasbinRuleCreate {
+	if v != nil {
+		_c.SetV7(*v)
+	}
+	return _c
+}
+
+// SetV8 sets the "V8" field.
+func (_c *CasbinRuleCreate) SetV8(v string) *CasbinRuleCreate {
+	_c.mutation.SetV8(v)
+	return _c
+}
+
+// SetNillableV8 sets the "V8" field if the given value is not nil.
+func (_c *CasbinRuleCreate) SetNillableV8(v *string) *CasbinRuleCreate {
+	if v != nil {
+		_c.SetV8(*v)
+	}
+	return _c
+}
+
+// SetV9 sets the "V9" field.
+func (_c *CasbinRuleCreate) SetV9(v string) *CasbinRuleCreate {
+	_c.mutation.SetV9(v)
+	return _c
+}
+
+// SetNillableV9 sets the "V9" field if the given value is not nil.
+func (_c *CasbinRuleCreate) SetNillableV9(v *string) *CasbinRuleCreate {
+	if v != nil {
+		_c.SetV9(*v)
+	}
+	return _c
+}
+
+// SetArity sets the "Arity" field.
+func (_c *CasbinRuleCreate) SetArity(v int) *CasbinRuleCreate {
+	_c.mutation.SetArity(v)
+	return _c
+}
+
+// SetNillableArity sets the "Arity" field if the given value is not nil.
+func (_c *CasbinRuleCreate) SetNillableArity(v *int) *CasbinRuleCreate {
+	if v != nil {
+		_c.SetArity(*v)
+	}
+	return _c
+}
+
+// SetRuleHash sets the "RuleHash" field.
+func (_c *CasbinRuleCreate) SetRuleHash(v string) *CasbinRuleCreate {
+	_c.mutation.SetRuleHash(v)
+	return _c
+}
+
+// SetNillableRuleHash sets the "RuleHash" field if the given value is not nil.
+func (_c *CasbinRuleCreate) SetNillableRuleHash(v *string) *CasbinRuleCreate {
+	if v != nil {
+		_c.SetRuleHash(*v)
+	}
+	return _c
+}
+
 // Mutation returns the CasbinRuleMutation object of the builder.
 func (_c *CasbinRuleCreate) Mutation() *CasbinRuleMutation {
 	return _c.mutation
@@ -180,6 +266,22 @@ func (_c *CasbinRuleCreate) defaults() {
 		v := casbinrule.DefaultV5
 		_c.mutation.SetV5(v)
 	}
+	if _, ok := _c.mutation.V6(); !ok {
+		v := casbinrule.DefaultV6
+		_c.mutation.SetV6(v)
+	}
+	if _, ok := _c.mutation.V7(); !ok {
+		v := casbinrule.DefaultV7
+		_c.mutation.SetV7(v)
+	}
+	if _, ok := _c.mutation.V8(); !ok {
+		v := casbinrule.DefaultV8
+		_c.mutation.SetV8(v)
+	}
+	if _, ok := _c.mutation.V9(); !ok {
+		v := casbinrule.DefaultV9
+		_c.mutation.SetV9(v)
+	}
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -205,6 +307,23 @@ func (_c *CasbinRuleCreate) check() error {
 	if _, ok := _c.mutation.V5(); !ok {
 		return &ValidationError{Name: "V5", err: errors.New(`ent: missing required field "CasbinRule.V5"`)}
 	}
+	if _, ok := _c.mutation.V6(); !ok {
+		return &ValidationError{Name: "V6", err: errors.New(`ent: missing required field "CasbinRule.V6"`)}
+	}
+	if _, ok := _c.mutation.V7(); !ok {
+		return &ValidationError{Name: "V7", err: errors.New(`ent: missing required field "CasbinRule.V7"`)}
+	}
+	if _, ok := _c.mutation.V8(); !ok {
+		return &ValidationError{Name: "V8", err: errors.New(`ent: missing required field "CasbinRule.V8"`)}
+	}
+	if _, ok := _c.mutation.V9(); !ok {
+		return &ValidationError{Name: "V9", err: errors.New(`ent: missing required field "CasbinRule.V9"`)}
+	}
+	if v, ok := _c.mutation.RuleHash(); ok {
+		if err := casbinrule.RuleHashValidator(v); err != nil {
+			return &ValidationError{Name: "RuleHash", err: fmt.Errorf(`ent: validator failed for field "CasbinRule.RuleHash": %w`, err)}
+		}
+	}
 	return nil
 }
 
@@ -231,6 +350,7 @@ func (_c *CasbinRuleCreate) createSpec() (*CasbinRule, *sqlgraph.CreateSpec) {
 		_node = &CasbinRule{config: _c.config}
 		_spec = sqlgraph.NewCreateSpec(casbinrule.Table, sqlgraph.NewFieldSpec(casbinrule.FieldID, field.TypeInt))
 	)
+	_spec.OnConflict = _c.conflict
 	if value, ok := _c.mutation.Ptype(); ok {
 		_spec.SetField(casbinrule.FieldPtype, field.TypeString, value)
 		_node.Ptype = value
@@ -259,14 +379,538 @@ func (_c *CasbinRuleCreate) createSpec() (*CasbinRule, *sqlgraph.CreateSpec) {
 		_spec.SetField(casbinrule.FieldV5, field.TypeString, value)
 		_node.V5 = value
 	}
+	if value, ok := _c.mutation.V6(); ok {
+		_spec.SetField(casbinrule.FieldV6, field.TypeString, value)
+		_node.V6 = value
+	}
+	if value, ok := _c.mutation.V7(); ok {
+		_spec.SetField(casbinrule.FieldV7, field.TypeString, value)
+		_node.V7 = value
+	}
+	if value, ok := _c.mutation.V8(); ok {
+		_spec.SetField(casbinrule.FieldV8, field.TypeString, value)
+		_node.V8 = value
+	}
+	if value, ok := _c.mutation.V9(); ok {
+		_spec.SetField(casbinrule.FieldV9, field.TypeString, value)
+		_node.V9 = value
+	}
+	if value, ok := _c.mutation.Arity(); ok {
+		_spec.SetField(casbinrule.FieldArity, field.TypeInt, value)
+		_node.Arity = &value
+	}
+	if value, ok := _c.mutation.RuleHash(); ok {
+		_spec.SetField(casbinrule.FieldRuleHash, field.TypeString, value)
+		_node.RuleHash = &value
+	}
 	return _node, _spec
 }
 
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.CasbinRule.Create().
+//		SetPtype(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.CasbinRuleUpsert) {
+//			SetPtype(v+v).
+//		}).
+//		Exec(ctx)
+func (_c *CasbinRuleCreate) OnConflict(opts ...sql.ConflictOption) *CasbinRuleUpsertOne {
+	_c.conflict = opts
+	return &CasbinRuleUpsertOne{
+		create: _c,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.CasbinRule.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (_c *CasbinRuleCreate) OnConflictColumns(columns ...string) *CasbinRuleUpsertOne {
+	_c.conflict = append(_c.conflict, sql.ConflictColumns(columns...))
+	return &CasbinRuleUpsertOne{
+		create: _c,
+	}
+}
+
+type (
+	// CasbinRuleUpsertOne is the builder for "upsert"-ing
+	//  one CasbinRule node.
+	CasbinRuleUpsertOne struct {
+		create *CasbinRuleCreate
+	}
+
+	// CasbinRuleUpsert is the "OnConflict" setter.
+	CasbinRuleUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetPtype sets the "Ptype" field.
+func (u *CasbinRuleUpsert) SetPtype(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldPtype, v)
+	return u
+}
+
+// UpdatePtype sets the "Ptype" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdatePtype() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldPtype)
+	return u
+}
+
+// SetV0 sets the "V0" field.
+func (u *CasbinRuleUpsert) SetV0(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV0, v)
+	return u
+}
+
+// UpdateV0 sets the "V0" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV0() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV0)
+	return u
+}
+
+// SetV1 sets the "V1" field.
+func (u *CasbinRuleUpsert) SetV1(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV1, v)
+	return u
+}
+
+// UpdateV1 sets the "V1" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV1() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV1)
+	return u
+}
+
+// SetV2 sets the "V2" field.
+func (u *CasbinRuleUpsert) SetV2(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV2, v)
+	return u
+}
+
+// UpdateV2 sets the "V2" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV2() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV2)
+	return u
+}
+
+// SetV3 sets the "V3" field.
+func (u *CasbinRuleUpsert) SetV3(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV3, v)
+	return u
+}
+
+// UpdateV3 sets the "V3" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV3() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV3)
+	return u
+}
+
+// SetV4 sets the "V4" field.
+func (u *CasbinRuleUpsert) SetV4(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV4, v)
+	return u
+}
+
+// UpdateV4 sets the "V4" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV4() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV4)
+	return u
+}
+
+// SetV5 sets the "V5" field.
+func (u *CasbinRuleUpsert) SetV5(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV5, v)
+	return u
+}
+
+// UpdateV5 sets the "V5" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV5() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV5)
+	return u
+}
+
+// SetV6 sets the "V6" field.
+func (u *CasbinRuleUpsert) SetV6(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV6, v)
+	return u
+}
+
+// UpdateV6 sets the "V6" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV6() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV6)
+	return u
+}
+
+// SetV7 sets the "V7" field.
+func (u *CasbinRuleUpsert) SetV7(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV7, v)
+	return u
+}
+
+// UpdateV7 sets the "V7" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV7() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV7)
+	return u
+}
+
+// SetV8 sets the "V8" field.
+func (u *CasbinRuleUpsert) SetV8(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV8, v)
+	return u
+}
+
+// UpdateV8 sets the "V8" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV8() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV8)
+	return u
+}
+
+// SetV9 sets the "V9" field.
+func (u *CasbinRuleUpsert) SetV9(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldV9, v)
+	return u
+}
+
+// UpdateV9 sets the "V9" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateV9() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldV9)
+	return u
+}
+
+// SetArity sets the "Arity" field.
+func (u *CasbinRuleUpsert) SetArity(v int) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldArity, v)
+	return u
+}
+
+// UpdateArity sets the "Arity" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateArity() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldArity)
+	return u
+}
+
+// AddArity adds v to the "Arity" field.
+func (u *CasbinRuleUpsert) AddArity(v int) *CasbinRuleUpsert {
+	u.Add(casbinrule.FieldArity, v)
+	return u
+}
+
+// ClearArity clears the value of the "Arity" field.
+func (u *CasbinRuleUpsert) ClearArity() *CasbinRuleUpsert {
+	u.SetNull(casbinrule.FieldArity)
+	return u
+}
+
+// SetRuleHash sets the "RuleHash" field.
+func (u *CasbinRuleUpsert) SetRuleHash(v string) *CasbinRuleUpsert {
+	u.Set(casbinrule.FieldRuleHash, v)
+	return u
+}
+
+// UpdateRuleHash sets the "RuleHash" field to the value that was provided on create.
+func (u *CasbinRuleUpsert) UpdateRuleHash() *CasbinRuleUpsert {
+	u.SetExcluded(casbinrule.FieldRuleHash)
+	return u
+}
+
+// ClearRuleHash clears the value of the "RuleHash" field.
+func (u *CasbinRuleUpsert) ClearRuleHash() *CasbinRuleUpsert {
+	u.SetNull(casbinrule.FieldRuleHash)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.CasbinRule.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *CasbinRuleUpsertOne) UpdateNewValues() *CasbinRuleUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.CasbinRule.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *CasbinRuleUpsertOne) Ignore() *CasbinRuleUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *CasbinRuleUpsertOne) DoNothing() *CasbinRuleUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the CasbinRuleCreate.OnConflict
+// documentation for more info.
+func (u *CasbinRuleUpsertOne) Update(set func(*CasbinRuleUpsert)) *CasbinRuleUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&CasbinRuleUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetPtype sets the "Ptype" field.
+func (u *CasbinRuleUpsertOne) SetPtype(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetPtype(v)
+	})
+}
+
+// UpdatePtype sets the "Ptype" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdatePtype() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdatePtype()
+	})
+}
+
+// SetV0 sets the "V0" field.
+func (u *CasbinRuleUpsertOne) SetV0(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV0(v)
+	})
+}
+
+// UpdateV0 sets the "V0" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV0() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV0()
+	})
+}
+
+// SetV1 sets the "V1" field.
+func (u *CasbinRuleUpsertOne) SetV1(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV1(v)
+	})
+}
+
+// UpdateV1 sets the "V1" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV1() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV1()
+	})
+}
+
+// SetV2 sets the "V2" field.
+func (u *CasbinRuleUpsertOne) SetV2(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV2(v)
+	})
+}
+
+// UpdateV2 sets the "V2" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV2() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV2()
+	})
+}
+
+// SetV3 sets the "V3" field.
+func (u *CasbinRuleUpsertOne) SetV3(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV3(v)
+	})
+}
+
+// UpdateV3 sets the "V3" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV3() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV3()
+	})
+}
+
+// SetV4 sets the "V4" field.
+func (u *CasbinRuleUpsertOne) SetV4(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV4(v)
+	})
+}
+
+// UpdateV4 sets the "V4" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV4() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV4()
+	})
+}
+
+// SetV5 sets the "V5" field.
+func (u *CasbinRuleUpsertOne) SetV5(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV5(v)
+	})
+}
+
+// UpdateV5 sets the "V5" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV5() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV5()
+	})
+}
+
+// SetV6 sets the "V6" field.
+func (u *CasbinRuleUpsertOne) SetV6(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV6(v)
+	})
+}
+
+// UpdateV6 sets the "V6" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV6() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV6()
+	})
+}
+
+// SetV7 sets the "V7" field.
+func (u *CasbinRuleUpsertOne) SetV7(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV7(v)
+	})
+}
+
+// UpdateV7 sets the "V7" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV7() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV7()
+	})
+}
+
+// SetV8 sets the "V8" field.
+func (u *CasbinRuleUpsertOne) SetV8(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV8(v)
+	})
+}
+
+// UpdateV8 sets the "V8" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV8() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV8()
+	})
+}
+
+// SetV9 sets the "V9" field.
+func (u *CasbinRuleUpsertOne) SetV9(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV9(v)
+	})
+}
+
+// UpdateV9 sets the "V9" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateV9() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV9()
+	})
+}
+
+// SetArity sets the "Arity" field.
+func (u *CasbinRuleUpsertOne) SetArity(v int) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetArity(v)
+	})
+}
+
+// AddArity adds v to the "Arity" field.
+func (u *CasbinRuleUpsertOne) AddArity(v int) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.AddArity(v)
+	})
+}
+
+// UpdateArity sets the "Arity" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateArity() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateArity()
+	})
+}
+
+// ClearArity clears the value of the "Arity" field.
+func (u *CasbinRuleUpsertOne) ClearArity() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.ClearArity()
+	})
+}
+
+// SetRuleHash sets the "RuleHash" field.
+func (u *CasbinRuleUpsertOne) SetRuleHash(v string) *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetRuleHash(v)
+	})
+}
+
+// UpdateRuleHash sets the "RuleHash" field to the value that was provided on create.
+func (u *CasbinRuleUpsertOne) UpdateRuleHash() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateRuleHash()
+	})
+}
+
+// ClearRuleHash clears the value of the "RuleHash" field.
+func (u *CasbinRuleUpsertOne) ClearRuleHash() *CasbinRuleUpsertOne {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.ClearRuleHash()
+	})
+}
+
+// Exec executes the query.
+func (u *CasbinRuleUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for CasbinRuleCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *CasbinRuleUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *CasbinRuleUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *CasbinRuleUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
 // CasbinRuleCreateBulk is the builder for creating many CasbinRule entities in bulk.
 type CasbinRuleCreateBulk struct {
 	config
 	err      error
 	builders []*CasbinRuleCreate
+	conflict []sql.ConflictOption
 }
 
 // Save creates the CasbinRule entities in the database.
@@ -296,6 +940,7 @@ func (_c *CasbinRuleCreateBulk) Save(ctx context.Context) ([]*CasbinRule, error)
 					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = _c.conflict
 					// Invoke the actual operation on the latest mutation in the chain.
 					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
@@ -349,3 +994,310 @@ func (_c *CasbinRuleCreateBulk) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.CasbinRule.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.CasbinRuleUpsert) {
+//			SetPtype(v+v).
+//		}).
+//		Exec(ctx)
+func (_c *CasbinRuleCreateBulk) OnConflict(opts ...sql.ConflictOption) *CasbinRuleUpsertBulk {
+	_c.conflict = opts
+	return &CasbinRuleUpsertBulk{
+		create: _c,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.CasbinRule.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (_c *CasbinRuleCreateBulk) OnConflictColumns(columns ...string) *CasbinRuleUpsertBulk {
+	_c.conflict = append(_c.conflict, sql.ConflictColumns(columns...))
+	return &CasbinRuleUpsertBulk{
+		create: _c,
+	}
+}
+
+// CasbinRuleUpsertBulk is the builder for "upsert"-ing
+// a bulk of CasbinRule nodes.
+type CasbinRuleUpsertBulk struct {
+	create *CasbinRuleCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.CasbinRule.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *CasbinRuleUpsertBulk) UpdateNewValues() *CasbinRuleUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.CasbinRule.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *CasbinRuleUpsertBulk) Ignore() *CasbinRuleUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *CasbinRuleUpsertBulk) DoNothing() *CasbinRuleUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the CasbinRuleCreateBulk.OnConflict
+// documentation for more info.
+func (u *CasbinRuleUpsertBulk) Update(set func(*CasbinRuleUpsert)) *CasbinRuleUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&CasbinRuleUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetPtype sets the "Ptype" field.
+func (u *CasbinRuleUpsertBulk) SetPtype(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetPtype(v)
+	})
+}
+
+// UpdatePtype sets the "Ptype" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdatePtype() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdatePtype()
+	})
+}
+
+// SetV0 sets the "V0" field.
+func (u *CasbinRuleUpsertBulk) SetV0(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV0(v)
+	})
+}
+
+// UpdateV0 sets the "V0" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV0() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV0()
+	})
+}
+
+// SetV1 sets the "V1" field.
+func (u *CasbinRuleUpsertBulk) SetV1(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV1(v)
+	})
+}
+
+// UpdateV1 sets the "V1" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV1() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV1()
+	})
+}
+
+// SetV2 sets the "V2" field.
+func (u *CasbinRuleUpsertBulk) SetV2(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV2(v)
+	})
+}
+
+// UpdateV2 sets the "V2" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV2() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV2()
+	})
+}
+
+// SetV3 sets the "V3" field.
+func (u *CasbinRuleUpsertBulk) SetV3(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV3(v)
+	})
+}
+
+// UpdateV3 sets the "V3" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV3() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV3()
+	})
+}
+
+// SetV4 sets the "V4" field.
+func (u *CasbinRuleUpsertBulk) SetV4(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV4(v)
+	})
+}
+
+// UpdateV4 sets the "V4" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV4() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV4()
+	})
+}
+
+// SetV5 sets the "V5" field.
+func (u *CasbinRuleUpsertBulk) SetV5(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV5(v)
+	})
+}
+
+// UpdateV5 sets the "V5" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV5() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV5()
+	})
+}
+
+// SetV6 sets the "V6" field.
+func (u *CasbinRuleUpsertBulk) SetV6(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV6(v)
+	})
+}
+
+// UpdateV6 sets the "V6" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV6() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV6()
+	})
+}
+
+// SetV7 sets the "V7" field.
+func (u *CasbinRuleUpsertBulk) SetV7(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV7(v)
+	})
+}
+
+// UpdateV7 sets the "V7" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV7() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV7()
+	})
+}
+
+// SetV8 sets the "V8" field.
+func (u *CasbinRuleUpsertBulk) SetV8(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV8(v)
+	})
+}
+
+// UpdateV8 sets the "V8" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV8() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV8()
+	})
+}
+
+// SetV9 sets the "V9" field.
+func (u *CasbinRuleUpsertBulk) SetV9(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetV9(v)
+	})
+}
+
+// UpdateV9 sets the "V9" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateV9() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateV9()
+	})
+}
+
+// SetArity sets the "Arity" field.
+func (u *CasbinRuleUpsertBulk) SetArity(v int) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetArity(v)
+	})
+}
+
+// AddArity adds v to the "Arity" field.
+func (u *CasbinRuleUpsertBulk) AddArity(v int) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.AddArity(v)
+	})
+}
+
+// UpdateArity sets the "Arity" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateArity() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateArity()
+	})
+}
+
+// ClearArity clears the value of the "Arity" field.
+func (u *CasbinRuleUpsertBulk) ClearArity() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.ClearArity()
+	})
+}
+
+// SetRuleHash sets the "RuleHash" field.
+func (u *CasbinRuleUpsertBulk) SetRuleHash(v string) *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.SetRuleHash(v)
+	})
+}
+
+// UpdateRuleHash sets the "RuleHash" field to the value that was provided on create.
+func (u *CasbinRuleUpsertBulk) UpdateRuleHash() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.UpdateRuleHash()
+	})
+}
+
+// ClearRuleHash clears the value of the "RuleHash" field.
+func (u *CasbinRuleUpsertBulk) ClearRuleHash() *CasbinRuleUpsertBulk {
+	return u.Update(func(s *CasbinRuleUpsert) {
+		s.ClearRuleHash()
+	})
+}
+
+// Exec executes the query.
+func (u *CasbinRuleUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("ent: OnConflict was set for builder %d. Set it on the CasbinRuleCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for CasbinRuleCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *CasbinRuleUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
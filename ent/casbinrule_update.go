@@ -125,6 +125,109 @@ func (_u *CasbinRuleUpdate) SetNillableV5(v *string) *CasbinRuleUpdate {
 	return _u
 }
 
+// SetV6 sets the "V6" field.
+func (_u *CasbinRuleUpdate) SetV6(v string) *CasbinRuleUpdate {
+	_u.mutation.SetV6(v)
+	return _u
+}
+
+// SetNillableV6 sets the "V6" field if the given value is not nil.
+func (_u *CasbinRuleUpdate) SetNillableV6(v *string) *CasbinRuleUpdate {
+	if v != nil {
+		_u.SetV6(*v)
+	}
+	return _u
+}
+
+// SetV7 sets the "V7" field.
+func (_u *CasbinRuleUpdate) SetV7(v string) *CasbinRuleUpdate {
+	_u.mutation.SetV7(v)
+	return _u
+}
+
+// SetNillableV7 sets the "V7" field if the given value is not nil.
+func (_u *CasbinRuleUpdate) SetNillableV7(v *string) *CasbinRuleUpdate {
+	if v != nil {
+		_u.SetV7(*v)
+	}
+	return _u
+}
+
+// SetV8 sets the "V8" field.
+func (_u *CasbinRuleUpdate) SetV8(v string) *CasbinRuleUpdate {
+	_u.mutation.SetV8(v)
+	return _u
+}
+
+// SetNillableV8 sets the "V8" field if the given value is not nil.
+func (_u *CasbinRuleUpdate) SetNillableV8(v *string) *CasbinRuleUpdate {
+	if v != nil {
+		_u.SetV8(*v)
+	}
+	return _u
+}
+
+// SetV9 sets the "V9" field.
+func (_u *CasbinRuleUpdate) SetV9(v string) *CasbinRuleUpdate {
+	_u.mutation.SetV9(v)
+	return _u
+}
+
+// SetNillableV9 sets the "V9" field if the given value is not nil.
+func (_u *CasbinRuleUpdate) SetNillableV9(v *string) *CasbinRuleUpdate {
+	if v != nil {
+		_u.SetV9(*v)
+	}
+	return _u
+}
+
+// SetArity sets the "Arity" field.
+func (_u *CasbinRuleUpdate) SetArity(v int) *CasbinRuleUpdate {
+	_u.mutation.ResetArity()
+	_u.mutation.SetArity(v)
+	return _u
+}
+
+// SetNillableArity sets the "Arity" field if the given value is not nil.
+func (_u *CasbinRuleUpdate) SetNillableArity(v *int) *CasbinRuleUpdate {
+	if v != nil {
+		_u.SetArity(*v)
+	}
+	return _u
+}
+
+// AddArity adds value to the "Arity" field.
+func (_u *CasbinRuleUpdate) AddArity(v int) *CasbinRuleUpdate {
+	_u.mutation.AddArity(v)
+	return _u
+}
+
+// ClearArity clears the value of the "Arity" field.
+func (_u *CasbinRuleUpdate) ClearArity() *CasbinRuleUpdate {
+	_u.mutation.ClearArity()
+	return _u
+}
+
+// SetRuleHash sets the "RuleHash" field.
+func (_u *CasbinRuleUpdate) SetRuleHash(v string) *CasbinRuleUpdate {
+	_u.mutation.SetRuleHash(v)
+	return _u
+}
+
+// SetNillableRuleHash sets the "RuleHash" field if the given value is not nil.
+func (_u *CasbinRuleUpdate) SetNillableRuleHash(v *string) *CasbinRuleUpdate {
+	if v != nil {
+		_u.SetRuleHash(*v)
+	}
+	return _u
+}
+
+// ClearRuleHash clears the value of the "RuleHash" field.
+func (_u *CasbinRuleUpdate) ClearRuleHash() *CasbinRuleUpdate {
+	_u.mutation.ClearRuleHash()
+	return _u
+}
+
 // Mutation returns the CasbinRuleMutation object of the builder.
 func (_u *CasbinRuleUpdate) Mutation() *CasbinRuleMutation {
 	return _u.mutation
@@ -157,7 +260,20 @@ func (_u *CasbinRuleUpdate) ExecX(ctx context.Context) {
 	}
 }
 
+// check runs all checks and user-defined validators on the builder.
+func (_u *CasbinRuleUpdate) check() error {
+	if v, ok := _u.mutation.RuleHash(); ok {
+		if err := casbinrule.RuleHashValidator(v); err != nil {
+			return &ValidationError{Name: "RuleHash", err: fmt.Errorf(`ent: validator failed for field "CasbinRule.RuleHash": %w`, err)}
+		}
+	}
+	return nil
+}
+
 func (_u *CasbinRuleUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(casbinrule.Table, casbinrule.Columns, sqlgraph.NewFieldSpec(casbinrule.FieldID, field.TypeInt))
 	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
@@ -187,6 +303,33 @@ func (_u *CasbinRuleUpdate) sqlSave(ctx context.Context) (_node int, err error)
 	if value, ok := _u.mutation.V5(); ok {
 		_spec.SetField(casbinrule.FieldV5, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.V6(); ok {
+		_spec.SetField(casbinrule.FieldV6, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.V7(); ok {
+		_spec.SetField(casbinrule.FieldV7, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.V8(); ok {
+		_spec.SetField(casbinrule.FieldV8, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.V9(); ok {
+		_spec.SetField(casbinrule.FieldV9, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Arity(); ok {
+		_spec.SetField(casbinrule.FieldArity, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedArity(); ok {
+		_spec.AddField(casbinrule.FieldArity, field.TypeInt, value)
+	}
+	if _u.mutation.ArityCleared() {
+		_spec.ClearField(casbinrule.FieldArity, field.TypeInt)
+	}
+	if value, ok := _u.mutation.RuleHash(); ok {
+		_spec.SetField(casbinrule.FieldRuleHash, field.TypeString, value)
+	}
+	if _u.mutation.RuleHashCleared() {
+		_spec.ClearField(casbinrule.FieldRuleHash, field.TypeString)
+	}
 	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{casbinrule.Label}
@@ -305,6 +448,109 @@ func (_u *CasbinRuleUpdateOne) SetNillableV5(v *string) *CasbinRuleUpdateOne {
 	return _u
 }
 
+// SetV6 sets the "V6" field.
+func (_u *CasbinRuleUpdateOne) SetV6(v string) *CasbinRuleUpdateOne {
+	_u.mutation.SetV6(v)
+	return _u
+}
+
+// SetNillableV6 sets the "V6" field if the given value is not nil.
+func (_u *CasbinRuleUpdateOne) SetNillableV6(v *string) *CasbinRuleUpdateOne {
+	if v != nil {
+		_u.SetV6(*v)
+	}
+	return _u
+}
+
+// SetV7 sets the "V7" field.
+func (_u *CasbinRuleUpdateOne) SetV7(v string) *CasbinRuleUpdateOne {
+	_u.mutation.SetV7(v)
+	return _u
+}
+
+// SetNillableV7 sets the "V7" field if the given value is not nil.
+func (_u *CasbinRuleUpdateOne) SetNillableV7(v *string) *CasbinRuleUpdateOne {
+	if v != nil {
+		_u.SetV7(*v)
+	}
+	return _u
+}
+
+// SetV8 sets the "V8" field.
+func (_u *CasbinRuleUpdateOne) SetV8(v string) *CasbinRuleUpdateOne {
+	_u.mutation.SetV8(v)
+	return _u
+}
+
+// SetNillableV8 sets the "V8" field if the given value is not nil.
+func (_u *CasbinRuleUpdateOne) SetNillableV8(v *string) *CasbinRuleUpdateOne {
+	if v != nil {
+		_u.SetV8(*v)
+	}
+	return _u
+}
+
+// SetV9 sets the "V9" field.
+func (_u *CasbinRuleUpdateOne) SetV9(v string) *CasbinRuleUpdateOne {
+	_u.mutation.SetV9(v)
+	return _u
+}
+
+// SetNillableV9 sets the "V9" field if the given value is not nil.
+func (_u *CasbinRuleUpdateOne) SetNillableV9(v *string) *CasbinRuleUpdateOne {
+	if v != nil {
+		_u.SetV9(*v)
+	}
+	return _u
+}
+
+// SetArity sets the "Arity" field.
+func (_u *CasbinRuleUpdateOne) SetArity(v int) *CasbinRuleUpdateOne {
+	_u.mutation.ResetArity()
+	_u.mutation.SetArity(v)
+	return _u
+}
+
+// SetNillableArity sets the "Arity" field if the given value is not nil.
+func (_u *CasbinRuleUpdateOne) SetNillableArity(v *int) *CasbinRuleUpdateOne {
+	if v != nil {
+		_u.SetArity(*v)
+	}
+	return _u
+}
+
+// AddArity adds value to the "Arity" field.
+func (_u *CasbinRuleUpdateOne) AddArity(v int) *CasbinRuleUpdateOne {
+	_u.mutation.AddArity(v)
+	return _u
+}
+
+// ClearArity clears the value of the "Arity" field.
+func (_u *CasbinRuleUpdateOne) ClearArity() *CasbinRuleUpdateOne {
+	_u.mutation.ClearArity()
+	return _u
+}
+
+// SetRuleHash sets the "RuleHash" field.
+func (_u *CasbinRuleUpdateOne) SetRuleHash(v string) *CasbinRuleUpdateOne {
+	_u.mutation.SetRuleHash(v)
+	return _u
+}
+
+// SetNillableRuleHash sets the "RuleHash" field if the given value is not nil.
+func (_u *CasbinRuleUpdateOne) SetNillableRuleHash(v *string) *CasbinRuleUpdateOne {
+	if v != nil {
+		_u.SetRuleHash(*v)
+	}
+	return _u
+}
+
+// ClearRuleHash clears the value of the "RuleHash" field.
+func (_u *CasbinRuleUpdateOne) ClearRuleHash() *CasbinRuleUpdateOne {
+	_u.mutation.ClearRuleHash()
+	return _u
+}
+
 // Mutation returns the CasbinRuleMutation object of the builder.
 func (_u *CasbinRuleUpdateOne) Mutation() *CasbinRuleMutation {
 	return _u.mutation
@@ -350,7 +596,20 @@ func (_u *CasbinRuleUpdateOne) ExecX(ctx context.Context) {
 	}
 }
 
+// check runs all checks and user-defined validators on the builder.
+func (_u *CasbinRuleUpdateOne) check() error {
+	if v, ok := _u.mutation.RuleHash(); ok {
+		if err := casbinrule.RuleHashValidator(v); err != nil {
+			return &ValidationError{Name: "RuleHash", err: fmt.Errorf(`ent: validator failed for field "CasbinRule.RuleHash": %w`, err)}
+		}
+	}
+	return nil
+}
+
 func (_u *CasbinRuleUpdateOne) sqlSave(ctx context.Context) (_node *CasbinRule, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(casbinrule.Table, casbinrule.Columns, sqlgraph.NewFieldSpec(casbinrule.FieldID, field.TypeInt))
 	id, ok := _u.mutation.ID()
 	if !ok {
@@ -397,6 +656,33 @@ func (_u *CasbinRuleUpdateOne) sqlSave(ctx context.Context) (_node *CasbinRule,
 	if value, ok := _u.mutation.V5(); ok {
 		_spec.SetField(casbinrule.FieldV5, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.V6(); ok {
+		_spec.SetField(casbinrule.FieldV6, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.V7(); ok {
+		_spec.SetField(casbinrule.FieldV7, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.V8(); ok {
+		_spec.SetField(casbinrule.FieldV8, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.V9(); ok {
+		_spec.SetField(casbinrule.FieldV9, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Arity(); ok {
+		_spec.SetField(casbinrule.FieldArity, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedArity(); ok {
+		_spec.AddField(casbinrule.FieldArity, field.TypeInt, value)
+	}
+	if _u.mutation.ArityCleared() {
+		_spec.ClearField(casbinrule.FieldArity, field.TypeInt)
+	}
+	if value, ok := _u.mutation.RuleHash(); ok {
+		_spec.SetField(casbinrule.FieldRuleHash, field.TypeString, value)
+	}
+	if _u.mutation.RuleHashCleared() {
+		_spec.ClearField(casbinrule.FieldRuleHash, field.TypeString)
+	}
 	_node = &CasbinRule{config: _u.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -3,13 +3,14 @@
 package migrate
 
 import (
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql/schema"
 	"entgo.io/ent/schema/field"
 )
 
 var (
-	// CasbinRulesColumns holds the columns for the "casbin_rules" table.
-	CasbinRulesColumns = []*schema.Column{
+	// CasbinRuleColumns holds the columns for the "casbin_rule" table.
+	CasbinRuleColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "ptype", Type: field.TypeString, Default: ""},
 		{Name: "v0", Type: field.TypeString, Default: ""},
@@ -18,18 +19,34 @@ var (
 		{Name: "v3", Type: field.TypeString, Default: ""},
 		{Name: "v4", Type: field.TypeString, Default: ""},
 		{Name: "v5", Type: field.TypeString, Default: ""},
+		{Name: "v6", Type: field.TypeString, Default: ""},
+		{Name: "v7", Type: field.TypeString, Default: ""},
+		{Name: "v8", Type: field.TypeString, Default: ""},
+		{Name: "v9", Type: field.TypeString, Default: ""},
+		{Name: "arity", Type: field.TypeInt, Nullable: true},
+		{Name: "rule_hash", Type: field.TypeString, Nullable: true, Size: 64},
 	}
-	// CasbinRulesTable holds the schema information for the "casbin_rules" table.
-	CasbinRulesTable = &schema.Table{
-		Name:       "casbin_rules",
-		Columns:    CasbinRulesColumns,
-		PrimaryKey: []*schema.Column{CasbinRulesColumns[0]},
+	// CasbinRuleTable holds the schema information for the "casbin_rule" table.
+	CasbinRuleTable = &schema.Table{
+		Name:       "casbin_rule",
+		Columns:    CasbinRuleColumns,
+		PrimaryKey: []*schema.Column{CasbinRuleColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "idx_casbin_rule_hash",
+				Unique:  true,
+				Columns: []*schema.Column{CasbinRuleColumns[13]},
+			},
+		},
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
-		CasbinRulesTable,
+		CasbinRuleTable,
 	}
 )
 
 func init() {
+	CasbinRuleTable.Annotation = &entsql.Annotation{
+		Table: "casbin_rule",
+	}
 }
@@ -39,6 +39,13 @@ type CasbinRuleMutation struct {
 	_V3           *string
 	_V4           *string
 	_V5           *string
+	_V6           *string
+	_V7           *string
+	_V8           *string
+	_V9           *string
+	_Arity        *int
+	add_Arity     *int
+	_RuleHash     *string
 	clearedFields map[string]struct{}
 	done          bool
 	oldValue      func(context.Context) (*CasbinRule, error)
@@ -395,6 +402,269 @@ func (m *CasbinRuleMutation) ResetV5() {
 	m._V5 = nil
 }
 
+// SetV6 sets the "V6" field.
+func (m *CasbinRuleMutation) SetV6(s string) {
+	m._V6 = &s
+}
+
+// V6 returns the value of the "V6" field in the mutation.
+func (m *CasbinRuleMutation) V6() (r string, exists bool) {
+	v := m._V6
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldV6 returns the old "V6" field's value of the CasbinRule entity.
+// If the CasbinRule object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *CasbinRuleMutation) OldV6(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldV6 is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldV6 requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldV6: %w", err)
+	}
+	return oldValue.V6, nil
+}
+
+// ResetV6 resets all changes to the "V6" field.
+func (m *CasbinRuleMutation) ResetV6() {
+	m._V6 = nil
+}
+
+// SetV7 sets the "V7" field.
+func (m *CasbinRuleMutation) SetV7(s string) {
+	m._V7 = &s
+}
+
+// V7 returns the value of the "V7" field in the mutation.
+func (m *CasbinRuleMutation) V7() (r string, exists bool) {
+	v := m._V7
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldV7 returns the old "V7" field's value of the CasbinRule entity.
+// If the CasbinRule object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *CasbinRuleMutation) OldV7(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldV7 is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldV7 requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldV7: %w", err)
+	}
+	return oldValue.V7, nil
+}
+
+// ResetV7 resets all changes to the "V7" field.
+func (m *CasbinRuleMutation) ResetV7() {
+	m._V7 = nil
+}
+
+// SetV8 sets the "V8" field.
+func (m *CasbinRuleMutation) SetV8(s string) {
+	m._V8 = &s
+}
+
+// V8 returns the value of the "V8" field in the mutation.
+func (m *CasbinRuleMutation) V8() (r string, exists bool) {
+	v := m._V8
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldV8 returns the old "V8" field's value of the CasbinRule entity.
+// If the CasbinRule object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *CasbinRuleMutation) OldV8(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldV8 is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldV8 requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldV8: %w", err)
+	}
+	return oldValue.V8, nil
+}
+
+// ResetV8 resets all changes to the "V8" field.
+func (m *CasbinRuleMutation) ResetV8() {
+	m._V8 = nil
+}
+
+// SetV9 sets the "V9" field.
+func (m *CasbinRuleMutation) SetV9(s string) {
+	m._V9 = &s
+}
+
+// V9 returns the value of the "V9" field in the mutation.
+func (m *CasbinRuleMutation) V9() (r string, exists bool) {
+	v := m._V9
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldV9 returns the old "V9" field's value of the CasbinRule entity.
+// If the CasbinRule object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *CasbinRuleMutation) OldV9(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldV9 is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldV9 requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldV9: %w", err)
+	}
+	return oldValue.V9, nil
+}
+
+// ResetV9 resets all changes to the "V9" field.
+func (m *CasbinRuleMutation) ResetV9() {
+	m._V9 = nil
+}
+
+// SetArity sets the "Arity" field.
+func (m *CasbinRuleMutation) SetArity(i int) {
+	m._Arity = &i
+	m.add_Arity = nil
+}
+
+// Arity returns the value of the "Arity" field in the mutation.
+func (m *CasbinRuleMutation) Arity() (r int, exists bool) {
+	v := m._Arity
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldArity returns the old "Arity" field's value of the CasbinRule entity.
+// If the CasbinRule object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *CasbinRuleMutation) OldArity(ctx context.Context) (v *int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldArity is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldArity requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldArity: %w", err)
+	}
+	return oldValue.Arity, nil
+}
+
+// AddArity adds i to the "Arity" field.
+func (m *CasbinRuleMutation) AddArity(i int) {
+	if m.add_Arity != nil {
+		*m.add_Arity += i
+	} else {
+		m.add_Arity = &i
+	}
+}
+
+// AddedArity returns the value that was added to the "Arity" field in this mutation.
+func (m *CasbinRuleMutation) AddedArity() (r int, exists bool) {
+	v := m.add_Arity
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearArity clears the value of the "Arity" field.
+func (m *CasbinRuleMutation) ClearArity() {
+	m._Arity = nil
+	m.add_Arity = nil
+	m.clearedFields[casbinrule.FieldArity] = struct{}{}
+}
+
+// ArityCleared returns if the "Arity" field was cleared in this mutation.
+func (m *CasbinRuleMutation) ArityCleared() bool {
+	_, ok := m.clearedFields[casbinrule.FieldArity]
+	return ok
+}
+
+// ResetArity resets all changes to the "Arity" field.
+func (m *CasbinRuleMutation) ResetArity() {
+	m._Arity = nil
+	m.add_Arity = nil
+	delete(m.clearedFields, casbinrule.FieldArity)
+}
+
+// SetRuleHash sets the "RuleHash" field.
+func (m *CasbinRuleMutation) SetRuleHash(s string) {
+	m._RuleHash = &s
+}
+
+// RuleHash returns the value of the "RuleHash" field in the mutation.
+func (m *CasbinRuleMutation) RuleHash() (r string, exists bool) {
+	v := m._RuleHash
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRuleHash returns the old "RuleHash" field's value of the CasbinRule entity.
+// If the CasbinRule object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *CasbinRuleMutation) OldRuleHash(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRuleHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRuleHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRuleHash: %w", err)
+	}
+	return oldValue.RuleHash, nil
+}
+
+// ClearRuleHash clears the value of the "RuleHash" field.
+func (m *CasbinRuleMutation) ClearRuleHash() {
+	m._RuleHash = nil
+	m.clearedFields[casbinrule.FieldRuleHash] = struct{}{}
+}
+
+// RuleHashCleared returns if the "RuleHash" field was cleared in this mutation.
+func (m *CasbinRuleMutation) RuleHashCleared() bool {
+	_, ok := m.clearedFields[casbinrule.FieldRuleHash]
+	return ok
+}
+
+// ResetRuleHash resets all changes to the "RuleHash" field.
+func (m *CasbinRuleMutation) ResetRuleHash() {
+	m._RuleHash = nil
+	delete(m.clearedFields, casbinrule.FieldRuleHash)
+}
+
 // Where appends a list predicates to the CasbinRuleMutation builder.
 func (m *CasbinRuleMutation) Where(ps ...predicate.CasbinRule) {
 	m.predicates = append(m.predicates, ps...)
@@ -429,7 +699,7 @@ func (m *CasbinRuleMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *CasbinRuleMutation) Fields() []string {
-	fields := make([]string, 0, 7)
+	fields := make([]string, 0, 13)
 	if m._Ptype != nil {
 		fields = append(fields, casbinrule.FieldPtype)
 	}
@@ -451,6 +721,24 @@ func (m *CasbinRuleMutation) Fields() []string {
 	if m._V5 != nil {
 		fields = append(fields, casbinrule.FieldV5)
 	}
+	if m._V6 != nil {
+		fields = append(fields, casbinrule.FieldV6)
+	}
+	if m._V7 != nil {
+		fields = append(fields, casbinrule.FieldV7)
+	}
+	if m._V8 != nil {
+		fields = append(fields, casbinrule.FieldV8)
+	}
+	if m._V9 != nil {
+		fields = append(fields, casbinrule.FieldV9)
+	}
+	if m._Arity != nil {
+		fields = append(fields, casbinrule.FieldArity)
+	}
+	if m._RuleHash != nil {
+		fields = append(fields, casbinrule.FieldRuleHash)
+	}
 	return fields
 }
 
@@ -473,6 +761,18 @@ func (m *CasbinRuleMutation) Field(name string) (ent.Value, bool) {
 		return m.V4()
 	case casbinrule.FieldV5:
 		return m.V5()
+	case casbinrule.FieldV6:
+		return m.V6()
+	case casbinrule.FieldV7:
+		return m.V7()
+	case casbinrule.FieldV8:
+		return m.V8()
+	case casbinrule.FieldV9:
+		return m.V9()
+	case casbinrule.FieldArity:
+		return m.Arity()
+	case casbinrule.FieldRuleHash:
+		return m.RuleHash()
 	}
 	return nil, false
 }
@@ -496,6 +796,18 @@ func (m *CasbinRuleMutation) OldField(ctx context.Context, name string) (ent.Val
 		return m.OldV4(ctx)
 	case casbinrule.FieldV5:
 		return m.OldV5(ctx)
+	case casbinrule.FieldV6:
+		return m.OldV6(ctx)
+	case casbinrule.FieldV7:
+		return m.OldV7(ctx)
+	case casbinrule.FieldV8:
+		return m.OldV8(ctx)
+	case casbinrule.FieldV9:
+		return m.OldV9(ctx)
+	case casbinrule.FieldArity:
+		return m.OldArity(ctx)
+	case casbinrule.FieldRuleHash:
+		return m.OldRuleHash(ctx)
 	}
 	return nil, fmt.Errorf("unknown CasbinRule field %s", name)
 }
@@ -554,6 +866,48 @@ func (m *CasbinRuleMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetV5(v)
 		return nil
+	case casbinrule.FieldV6:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetV6(v)
+		return nil
+	case casbinrule.FieldV7:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetV7(v)
+		return nil
+	case casbinrule.FieldV8:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetV8(v)
+		return nil
+	case casbinrule.FieldV9:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetV9(v)
+		return nil
+	case casbinrule.FieldArity:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetArity(v)
+		return nil
+	case casbinrule.FieldRuleHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRuleHash(v)
+		return nil
 	}
 	return fmt.Errorf("unknown CasbinRule field %s", name)
 }
@@ -561,13 +915,21 @@ func (m *CasbinRuleMutation) SetField(name string, value ent.Value) error {
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
 func (m *CasbinRuleMutation) AddedFields() []string {
-	return nil
+	var fields []string
+	if m.add_Arity != nil {
+		fields = append(fields, casbinrule.FieldArity)
+	}
+	return fields
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
 func (m *CasbinRuleMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case casbinrule.FieldArity:
+		return m.AddedArity()
+	}
 	return nil, false
 }
 
@@ -576,6 +938,13 @@ func (m *CasbinRuleMutation) AddedField(name string) (ent.Value, bool) {
 // type.
 func (m *CasbinRuleMutation) AddField(name string, value ent.Value) error {
 	switch name {
+	case casbinrule.FieldArity:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddArity(v)
+		return nil
 	}
 	return fmt.Errorf("unknown CasbinRule numeric field %s", name)
 }
@@ -583,7 +952,14 @@ func (m *CasbinRuleMutation) AddField(name string, value ent.Value) error {
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
 func (m *CasbinRuleMutation) ClearedFields() []string {
-	return nil
+	var fields []string
+	if m.FieldCleared(casbinrule.FieldArity) {
+		fields = append(fields, casbinrule.FieldArity)
+	}
+	if m.FieldCleared(casbinrule.FieldRuleHash) {
+		fields = append(fields, casbinrule.FieldRuleHash)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
@@ -596,6 +972,14 @@ func (m *CasbinRuleMutation) FieldCleared(name string) bool {
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
 func (m *CasbinRuleMutation) ClearField(name string) error {
+	switch name {
+	case casbinrule.FieldArity:
+		m.ClearArity()
+		return nil
+	case casbinrule.FieldRuleHash:
+		m.ClearRuleHash()
+		return nil
+	}
 	return fmt.Errorf("unknown CasbinRule nullable field %s", name)
 }
 
@@ -624,6 +1008,24 @@ func (m *CasbinRuleMutation) ResetField(name string) error {
 	case casbinrule.FieldV5:
 		m.ResetV5()
 		return nil
+	case casbinrule.FieldV6:
+		m.ResetV6()
+		return nil
+	case casbinrule.FieldV7:
+		m.ResetV7()
+		return nil
+	case casbinrule.FieldV8:
+		m.ResetV8()
+		return nil
+	case casbinrule.FieldV9:
+		m.ResetV9()
+		return nil
+	case casbinrule.FieldArity:
+		m.ResetArity()
+		return nil
+	case casbinrule.FieldRuleHash:
+		m.ResetRuleHash()
+		return nil
 	}
 	return fmt.Errorf("unknown CasbinRule field %s", name)
 }
@@ -41,4 +41,24 @@ func init() {
 	casbinruleDescV5 := casbinruleFields[6].Descriptor()
 	// casbinrule.DefaultV5 holds the default value on creation for the V5 field.
 	casbinrule.DefaultV5 = casbinruleDescV5.Default.(string)
+	// casbinruleDescV6 is the schema descriptor for V6 field.
+	casbinruleDescV6 := casbinruleFields[7].Descriptor()
+	// casbinrule.DefaultV6 holds the default value on creation for the V6 field.
+	casbinrule.DefaultV6 = casbinruleDescV6.Default.(string)
+	// casbinruleDescV7 is the schema descriptor for V7 field.
+	casbinruleDescV7 := casbinruleFields[8].Descriptor()
+	// casbinrule.DefaultV7 holds the default value on creation for the V7 field.
+	casbinrule.DefaultV7 = casbinruleDescV7.Default.(string)
+	// casbinruleDescV8 is the schema descriptor for V8 field.
+	casbinruleDescV8 := casbinruleFields[9].Descriptor()
+	// casbinrule.DefaultV8 holds the default value on creation for the V8 field.
+	casbinrule.DefaultV8 = casbinruleDescV8.Default.(string)
+	// casbinruleDescV9 is the schema descriptor for V9 field.
+	casbinruleDescV9 := casbinruleFields[10].Descriptor()
+	// casbinrule.DefaultV9 holds the default value on creation for the V9 field.
+	casbinrule.DefaultV9 = casbinruleDescV9.Default.(string)
+	// casbinruleDescRuleHash is the schema descriptor for RuleHash field.
+	casbinruleDescRuleHash := casbinruleFields[12].Descriptor()
+	// casbinrule.RuleHashValidator is a validator for the "RuleHash" field. It is called by the builders before save.
+	casbinrule.RuleHashValidator = casbinruleDescRuleHash.Validators[0].(func(string) error)
 }
@@ -2,6 +2,8 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
 )
@@ -21,6 +23,24 @@ func (CasbinRule) Fields() []ent.Field {
 		field.String("V3").Default(""),
 		field.String("V4").Default(""),
 		field.String("V5").Default(""),
+		field.String("V6").Default(""),
+		field.String("V7").Default(""),
+		field.String("V8").Default(""),
+		field.String("V9").Default(""),
+		// Arity is the number of fields of the rule, so that empty
+		// fields round-trip. It is NULL for rules stored by older versions.
+		field.Int("Arity").Optional().Nillable(),
+		// RuleHash is the hex SHA-256 of the ptype, the fields and the arity
+		// of the rule, which makes rules unique. It is NULL for rules stored
+		// by older versions until they are migrated.
+		field.String("RuleHash").MaxLen(64).Optional().Nillable(),
+	}
+}
+
+// Annotations of the CasbinRule.
+func (CasbinRule) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "casbin_rule"},
 	}
 }
 
@@ -29,8 +49,13 @@ func (CasbinRule) Edges() []ent.Edge {
 	return nil
 }
 
-func (CasbinRule) Index() []ent.Index {
+// Indexes of the CasbinRule.
+func (CasbinRule) Indexes() []ent.Index {
 	return []ent.Index{
-		index.Fields("Ptype", "V0", "V1", "V2", "V3", "V4", "V5").Unique(),
+		// The rule columns themselves are not indexed, as MySQL only indexes
+		// a prefix of each and PostgreSQL limits the size of an index entry.
+		index.Fields("RuleHash").
+			Unique().
+			StorageKey("idx_casbin_rule_hash"),
 	}
 }
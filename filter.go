@@ -0,0 +1,330 @@
+// Copyright 2021 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entadapter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/casbin/ent-adapter/ent/casbinrule"
+	"github.com/casbin/ent-adapter/ent/predicate"
+	"github.com/pkg/errors"
+)
+
+// Filter selects the rules loaded by LoadFilteredPolicy. A rule matches if,
+// for every non-empty field of the filter, its column equals one of the values.
+type Filter struct {
+	Ptype []string
+	V0    []string
+	V1    []string
+	V2    []string
+	V3    []string
+	V4    []string
+	V5    []string
+	V6    []string
+	V7    []string
+	V8    []string
+	V9    []string
+}
+
+// predicates returns the predicates matching the rules selected by the filter.
+func (f Filter) predicates() []predicate.CasbinRule {
+	cond := make([]predicate.CasbinRule, 0)
+	if len(f.Ptype) != 0 {
+		cond = append(cond, casbinrule.PtypeIn(f.Ptype...))
+	}
+	if len(f.V0) != 0 {
+		cond = append(cond, casbinrule.V0In(f.V0...))
+	}
+	if len(f.V1) != 0 {
+		cond = append(cond, casbinrule.V1In(f.V1...))
+	}
+	if len(f.V2) != 0 {
+		cond = append(cond, casbinrule.V2In(f.V2...))
+	}
+	if len(f.V3) != 0 {
+		cond = append(cond, casbinrule.V3In(f.V3...))
+	}
+	if len(f.V4) != 0 {
+		cond = append(cond, casbinrule.V4In(f.V4...))
+	}
+	if len(f.V5) != 0 {
+		cond = append(cond, casbinrule.V5In(f.V5...))
+	}
+	if len(f.V6) != 0 {
+		cond = append(cond, casbinrule.V6In(f.V6...))
+	}
+	if len(f.V7) != 0 {
+		cond = append(cond, casbinrule.V7In(f.V7...))
+	}
+	if len(f.V8) != 0 {
+		cond = append(cond, casbinrule.V8In(f.V8...))
+	}
+	if len(f.V9) != 0 {
+		cond = append(cond, casbinrule.V9In(f.V9...))
+	}
+	return cond
+}
+
+// BatchFilter selects the rules matching any of its filters,
+// e.g. the policies of several tenants, in a single query.
+type BatchFilter struct {
+	Filters []Filter
+}
+
+// anyFilterPredicates returns the predicates matching the rules selected by any of filters.
+func anyFilterPredicates(filters []Filter) []predicate.CasbinRule {
+	if len(filters) == 0 {
+		return []predicate.CasbinRule{func(s *entsql.Selector) {
+			s.Where(entsql.False())
+		}}
+	}
+	preds := make([]predicate.CasbinRule, 0, len(filters))
+	for _, f := range filters {
+		cond := f.predicates()
+		if len(cond) == 0 {
+			// An empty filter selects every rule.
+			return nil
+		}
+		preds = append(preds, casbinrule.And(cond...))
+	}
+	return []predicate.CasbinRule{casbinrule.Or(preds...)}
+}
+
+// loadFilterPredicates returns the predicates for a filter passed to LoadFilteredPolicy.
+func loadFilterPredicates(filter interface{}) ([]predicate.CasbinRule, error) {
+	switch f := filter.(type) {
+	case Filter:
+		return f.predicates(), nil
+	case *Filter:
+		if f == nil {
+			return nil, errors.New("invalid filter: nil *Filter")
+		}
+		return f.predicates(), nil
+	case []Filter:
+		return anyFilterPredicates(f), nil
+	case BatchFilter:
+		return anyFilterPredicates(f.Filters), nil
+	case *BatchFilter:
+		if f == nil {
+			return nil, errors.New("invalid filter: nil *BatchFilter")
+		}
+		return anyFilterPredicates(f.Filters), nil
+	case FilterExpr:
+		return f.predicates()
+	case []predicate.CasbinRule:
+		return f, nil
+	default:
+		return nil, fmt.Errorf("invalid filter type: %v", reflect.TypeOf(filter))
+	}
+}
+
+// FilterExpr is a composable filter for LoadFilteredPolicy. Expressions are
+// built with the functions below and refer to the columns "ptype" and "v0"
+// to "v9". For example, all rules in the tenant-* domains plus all g2 rules:
+//
+//	entadapter.Or(
+//		entadapter.Glob("v1", "tenant-*"),
+//		entadapter.In("ptype", "g2"),
+//	)
+type FilterExpr struct {
+	pred predicate.CasbinRule
+	err  error
+}
+
+// predicates returns the predicates matching the rules selected by the expression.
+// The zero FilterExpr is invalid rather than matching every rule.
+func (e FilterExpr) predicates() ([]predicate.CasbinRule, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.pred == nil {
+		return nil, errors.New("invalid filter: zero FilterExpr")
+	}
+	return []predicate.CasbinRule{e.pred}, nil
+}
+
+// fieldExpr returns an expression applying pred to the column field.
+func fieldExpr(field string, pred func(column string) predicate.CasbinRule) FilterExpr {
+	for _, column := range ruleColumns {
+		if column == field {
+			return FilterExpr{pred: pred(column)}
+		}
+	}
+	return FilterExpr{err: fmt.Errorf("invalid filter field: %q", field)}
+}
+
+// In matches rules whose field equals one of values.
+func In(field string, values ...string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return entsql.FieldIn(column, values...)
+	})
+}
+
+// NotIn matches rules whose field equals none of values.
+func NotIn(field string, values ...string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return entsql.FieldNotIn(column, values...)
+	})
+}
+
+// HasPrefix matches rules whose field starts with prefix.
+func HasPrefix(field, prefix string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return entsql.FieldHasPrefix(column, prefix)
+	})
+}
+
+// HasSuffix matches rules whose field ends with suffix.
+func HasSuffix(field, suffix string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return entsql.FieldHasSuffix(column, suffix)
+	})
+}
+
+// Contains matches rules whose field contains substr.
+func Contains(field, substr string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return entsql.FieldContains(column, substr)
+	})
+}
+
+// Like matches rules whose field matches the SQL LIKE pattern.
+func Like(field, pattern string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return func(s *entsql.Selector) {
+			s.Where(entsql.Like(s.C(column), pattern))
+		}
+	})
+}
+
+// Glob matches rules whose field matches the pattern, where "*" matches any
+// sequence of characters and "?" matches a single character.
+func Glob(field, pattern string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return func(s *entsql.Selector) {
+			s.Where(entsql.P(func(b *entsql.Builder) {
+				b.Ident(s.C(column)).WriteOp(entsql.OpLike).Arg(globToLike(pattern))
+				// Backslash is the default escape character of MySQL and PostgreSQL only.
+				if b.Dialect() == dialect.SQLite {
+					b.WriteString(" ESCAPE ").Arg(`\`)
+				}
+			}))
+		}
+	})
+}
+
+// globToLike converts a glob pattern to a LIKE pattern escaped with backslashes.
+func globToLike(pattern string) string {
+	var b strings.Builder
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// Regex matches rules whose field matches the regular expression.
+// It is supported on MySQL and PostgreSQL, using their own regex syntax.
+func Regex(field, pattern string) FilterExpr {
+	return fieldExpr(field, func(column string) predicate.CasbinRule {
+		return func(s *entsql.Selector) {
+			var op string
+			switch s.Dialect() {
+			case dialect.MySQL:
+				op = " REGEXP "
+			case dialect.Postgres:
+				op = " ~ "
+			default:
+				s.AddError(fmt.Errorf("regex filters are not supported by %s", s.Dialect()))
+				return
+			}
+			s.Where(entsql.P(func(b *entsql.Builder) {
+				b.Ident(s.C(column)).WriteString(op).Arg(pattern)
+			}))
+		}
+	})
+}
+
+// Where matches rules that satisfy all the given ent predicates.
+// Without predicates it is invalid rather than matching every rule.
+func Where(preds ...predicate.CasbinRule) FilterExpr {
+	if len(preds) == 0 {
+		return FilterExpr{err: errors.New("invalid filter: Where without predicates")}
+	}
+	return FilterExpr{pred: casbinrule.And(preds...)}
+}
+
+// And matches rules that match all of exprs.
+// Without expressions it is invalid rather than matching every rule.
+func And(exprs ...FilterExpr) FilterExpr {
+	if len(exprs) == 0 {
+		return FilterExpr{err: errors.New("invalid filter: And without expressions")}
+	}
+	preds, err := exprPredicates(exprs)
+	if err != nil {
+		return FilterExpr{err: err}
+	}
+	return FilterExpr{pred: casbinrule.And(preds...)}
+}
+
+// Or matches rules that match any of exprs.
+// Without expressions it matches no rule, like an empty []Filter.
+func Or(exprs ...FilterExpr) FilterExpr {
+	if len(exprs) == 0 {
+		return FilterExpr{pred: func(s *entsql.Selector) {
+			s.Where(entsql.False())
+		}}
+	}
+	preds, err := exprPredicates(exprs)
+	if err != nil {
+		return FilterExpr{err: err}
+	}
+	return FilterExpr{pred: casbinrule.Or(preds...)}
+}
+
+// Not matches rules that do not match expr.
+func Not(expr FilterExpr) FilterExpr {
+	preds, err := exprPredicates([]FilterExpr{expr})
+	if err != nil {
+		return FilterExpr{err: err}
+	}
+	return FilterExpr{pred: casbinrule.Not(casbinrule.And(preds...))}
+}
+
+// exprPredicates returns the predicates of exprs, or the first error among them.
+func exprPredicates(exprs []FilterExpr) ([]predicate.CasbinRule, error) {
+	preds := make([]predicate.CasbinRule, 0, len(exprs))
+	for _, expr := range exprs {
+		p, err := expr.predicates()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, p...)
+	}
+	return preds, nil
+}
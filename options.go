@@ -0,0 +1,365 @@
+// Copyright 2021 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entadapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/casbin/ent-adapter/ent"
+	"github.com/pkg/errors"
+)
+
+// Option configures an Adapter. Options are validated when they are applied,
+// and NewAdapter returns an error if the given options conflict.
+type Option func(a *Adapter) error
+
+// OnConflict controls what happens when a rule being added is already stored.
+type OnConflict int
+
+const (
+	// OnConflictError makes adding an existing rule fail with ErrPolicyExists.
+	OnConflictError OnConflict = iota
+	// OnConflictIgnore skips rules that are already stored.
+	OnConflictIgnore
+	// OnConflictUpsert overwrites rules that are already stored with the new values.
+	OnConflictUpsert
+)
+
+// SaveMode controls how SavePolicy writes the policy to the storage.
+type SaveMode int
+
+const (
+	// SaveModeRewrite deletes all stored rules and inserts the whole policy.
+	SaveModeRewrite SaveMode = iota
+	// SaveModeDiff compares the stored rules with the policy and only deletes
+	// and inserts the rules that changed. Unchanged rules keep their rows, so
+	// the rules are loaded back in a different order if rules were added before
+	// existing ones. Models that depend on the rule order, such as those using
+	// the priority effect, should use SaveModeRewrite.
+	SaveModeDiff
+)
+
+// BulkStrategy controls how rules are inserted in bulk by SavePolicy,
+// AddPolicies and the update methods.
+type BulkStrategy int
+
+const (
+	// BulkStrategyInsert inserts rules with multi-row INSERT statements built by ent.
+	BulkStrategyInsert BulkStrategy = iota
+	// BulkStrategyCopy streams rules into a temporary table with COPY FROM STDIN
+	// and merges them into the policy table. It requires the pgx driver.
+	BulkStrategyCopy
+)
+
+// DefaultBatchSize is the maximum number of rules inserted or deleted per statement.
+const DefaultBatchSize = 5000
+
+// DefaultPageSize is the number of rules LoadPolicy reads per query.
+const DefaultPageSize = 10000
+
+var tableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithTableName sets the name of the table that stores the policy rules.
+// It defaults to DefaultTableName.
+func WithTableName(name string) Option {
+	return func(a *Adapter) error {
+		if !tableNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid table name: %q", name)
+		}
+		a.tableName = name
+		return nil
+	}
+}
+
+// WithTablePrefix sets a prefix that is prepended to the table name,
+// e.g. "app1_" stores the policy rules in "app1_casbin_rule".
+func WithTablePrefix(prefix string) Option {
+	return func(a *Adapter) error {
+		if prefix != "" && !tableNameRegexp.MatchString(prefix) {
+			return fmt.Errorf("invalid table prefix: %q", prefix)
+		}
+		a.tablePrefix = prefix
+		return nil
+	}
+}
+
+// WithOnConflict sets how adding a rule that is already stored is handled.
+// It defaults to OnConflictError.
+func WithOnConflict(mode OnConflict) Option {
+	return func(a *Adapter) error {
+		switch mode {
+		case OnConflictError, OnConflictIgnore, OnConflictUpsert:
+			a.onConflict = mode
+			return nil
+		default:
+			return fmt.Errorf("invalid on conflict mode: %d", mode)
+		}
+	}
+}
+
+// WithoutAutoMigrate disables the creation of the policy table
+// when the adapter is constructed.
+func WithoutAutoMigrate() Option {
+	return func(a *Adapter) error {
+		a.autoMigrate = false
+		return nil
+	}
+}
+
+// WithBatchSize sets the maximum number of rules inserted or deleted per
+// statement by SavePolicy, AddPolicies and the update methods. Batches are
+// made smaller if needed to stay within the bind parameter limit of the
+// database. It defaults to DefaultBatchSize.
+func WithBatchSize(size int) Option {
+	return func(a *Adapter) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid batch size: %d", size)
+		}
+		a.batchSize = size
+		return nil
+	}
+}
+
+// WithPageSize sets the number of rules LoadPolicy, LoadFilteredPolicy and
+// LoadPolicyPages read per query. It defaults to DefaultPageSize.
+func WithPageSize(size int) Option {
+	return func(a *Adapter) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid page size: %d", size)
+		}
+		a.pageSize = size
+		return nil
+	}
+}
+
+// WithParallelLoad makes LoadPolicy load the rules of each ptype with separate
+// queries, running at most maxConcurrency of them at a time over the connection
+// pool. The loaded policy is the same as with a sequential load, but all
+// rules are held in memory until they are added to the model.
+func WithParallelLoad(maxConcurrency int) Option {
+	return func(a *Adapter) error {
+		if maxConcurrency <= 0 {
+			return fmt.Errorf("invalid max concurrency: %d", maxConcurrency)
+		}
+		a.parallelism = maxConcurrency
+		return nil
+	}
+}
+
+// WithReplica makes the adapter load the policy with client, e.g. one opened
+// on a read replica, while writes and migrations use the primary client.
+func WithReplica(client *ent.Client) Option {
+	return func(a *Adapter) error {
+		if client == nil {
+			return errors.New("replica client must not be nil")
+		}
+		a.replica = client
+		return nil
+	}
+}
+
+// WithReplicaDB makes the adapter load the policy from db, e.g. a read
+// replica, while writes and migrations use the primary database. db must
+// use the same dialect as the primary.
+func WithReplicaDB(db *sql.DB) Option {
+	return func(a *Adapter) error {
+		if db == nil {
+			return errors.New("replica database must not be nil")
+		}
+		a.replicaDB = db
+		return nil
+	}
+}
+
+// WithReadYourWrites makes the adapter load the policy from the primary
+// instead of the replica for the given time after each write, so that
+// writes are seen even if the replica lags behind.
+func WithReadYourWrites(window time.Duration) Option {
+	return func(a *Adapter) error {
+		if window <= 0 {
+			return fmt.Errorf("invalid read-your-writes window: %v", window)
+		}
+		a.readYourWrites = window
+		return nil
+	}
+}
+
+// WithMaxOpenConns sets the maximum number of open connections to the database.
+// Like the other pool options, it only applies to adapters created by NewAdapter.
+func WithMaxOpenConns(n int) Option {
+	return func(a *Adapter) error {
+		a.pool = append(a.pool, func(db *sql.DB) { db.SetMaxOpenConns(n) })
+		return nil
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections to the database.
+func WithMaxIdleConns(n int) Option {
+	return func(a *Adapter) error {
+		a.pool = append(a.pool, func(db *sql.DB) { db.SetMaxIdleConns(n) })
+		return nil
+	}
+}
+
+// WithConnMaxLifetime sets the maximum time a connection to the database may be reused.
+func WithConnMaxLifetime(d time.Duration) Option {
+	return func(a *Adapter) error {
+		a.pool = append(a.pool, func(db *sql.DB) { db.SetConnMaxLifetime(d) })
+		return nil
+	}
+}
+
+// WithConnMaxIdleTime sets the maximum time a connection to the database may be idle.
+func WithConnMaxIdleTime(d time.Duration) Option {
+	return func(a *Adapter) error {
+		a.pool = append(a.pool, func(db *sql.DB) { db.SetConnMaxIdleTime(d) })
+		return nil
+	}
+}
+
+// WithSaveMode sets how SavePolicy writes the policy. It defaults to SaveModeRewrite.
+func WithSaveMode(mode SaveMode) Option {
+	return func(a *Adapter) error {
+		switch mode {
+		case SaveModeRewrite, SaveModeDiff:
+			a.saveMode = mode
+			return nil
+		default:
+			return fmt.Errorf("invalid save mode: %d", mode)
+		}
+	}
+}
+
+// WithBulkStrategy sets how rules are inserted in bulk.
+// It defaults to BulkStrategyInsert.
+func WithBulkStrategy(strategy BulkStrategy) Option {
+	return func(a *Adapter) error {
+		switch strategy {
+		case BulkStrategyInsert, BulkStrategyCopy:
+			a.bulkStrategy = strategy
+			return nil
+		default:
+			return fmt.Errorf("invalid bulk strategy: %d", strategy)
+		}
+	}
+}
+
+// WithLogger logs every statement the adapter executes with logger,
+// e.g. WithLogger(log.Println).
+func WithLogger(logger func(...any)) Option {
+	return func(a *Adapter) error {
+		if logger == nil {
+			return errors.New("logger must not be nil")
+		}
+		a.logger = logger
+		return nil
+	}
+}
+
+// WithContext sets the context used by the methods that do not take one,
+// such as LoadPolicy and AddPolicy. It defaults to context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(a *Adapter) error {
+		if ctx == nil {
+			return errors.New("context must not be nil")
+		}
+		a.ctx = ctx
+		return nil
+	}
+}
+
+// WithReadOnly makes the adapter reject every write with ErrReadOnly.
+// A read-only adapter never creates the policy table.
+func WithReadOnly() Option {
+	return func(a *Adapter) error {
+		a.readOnly = true
+		return nil
+	}
+}
+
+// WithTxIsolation sets the isolation level of the transactions the adapter
+// starts for writes. It defaults to the isolation level of the database.
+func WithTxIsolation(level sql.IsolationLevel) Option {
+	return func(a *Adapter) error {
+		if level < sql.LevelDefault || level > sql.LevelLinearizable {
+			return fmt.Errorf("invalid isolation level: %d", level)
+		}
+		a.isolation = level
+		return nil
+	}
+}
+
+// WithSnapshotLoad makes LoadPolicy, LoadFilteredPolicy and LoadPolicyPages
+// read in a read-only transaction at REPEATABLE READ, or its SQLite
+// equivalent. All pages of a load then see the same state of the table, so a
+// load never sees part of a concurrent SavePolicy. It cannot be combined with
+// WithParallelLoad, whose queries run in separate transactions.
+func WithSnapshotLoad() Option {
+	return func(a *Adapter) error {
+		a.snapshotLoad = true
+		return nil
+	}
+}
+
+// WithRetry makes the adapter run its transactions again when they fail with
+// a transient error, as configured by policy. The whole transaction is run
+// again, including the function passed to WithTx, which must allow that.
+// Writes joining a transaction of the caller are not retried.
+func WithRetry(policy RetryPolicy) Option {
+	return func(a *Adapter) error {
+		switch {
+		case policy.MaxAttempts < 1:
+			return fmt.Errorf("invalid max attempts: %d", policy.MaxAttempts)
+		case policy.InitialBackoff < 0 || policy.MaxBackoff < 0:
+			return errors.New("invalid retry backoff: must not be negative")
+		}
+		a.retry = &policy
+		return nil
+	}
+}
+
+// validate reports options that were applied together but contradict each other.
+func (a *Adapter) validate() error {
+	switch {
+	case a.replica != nil && a.replicaDB != nil:
+		return errors.New("conflicting options: WithReplica and WithReplicaDB")
+	case a.readYourWrites != 0 && a.replica == nil && a.replicaDB == nil:
+		return errors.New("conflicting options: WithReadYourWrites requires WithReplica or WithReplicaDB")
+	case a.snapshotLoad && a.parallelism != 0:
+		return errors.New("conflicting options: WithSnapshotLoad and WithParallelLoad")
+	case len(a.pool) != 0 && !a.owned:
+		return errors.New("connection pool options only apply to adapters created by NewAdapter")
+	}
+	if !a.readOnly {
+		return nil
+	}
+	switch {
+	case a.batchSize != 0:
+		return errors.New("conflicting options: WithReadOnly and WithBatchSize")
+	case a.onConflict != OnConflictError:
+		return errors.New("conflicting options: WithReadOnly and WithOnConflict")
+	case a.isolation != sql.LevelDefault:
+		return errors.New("conflicting options: WithReadOnly and WithTxIsolation")
+	case a.saveMode != SaveModeRewrite:
+		return errors.New("conflicting options: WithReadOnly and WithSaveMode")
+	case a.bulkStrategy != BulkStrategyInsert:
+		return errors.New("conflicting options: WithReadOnly and WithBulkStrategy")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entadapter
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// RetryPolicy controls how the transactions of the adapter are run again
+// when they fail with a transient error, such as a deadlock.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a transaction is run,
+	// including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. It doubles for
+	// every further retry, up to MaxBackoff if that is set. Each wait is
+	// shortened by a random amount of up to half of it, so that concurrent
+	// transactions do not collide again.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Retryable reports whether a transaction failing with err is retried.
+	// It defaults to IsRetryable.
+	Retryable func(err error) bool
+}
+
+// IsRetryable reports whether err is a serialization failure or a deadlock,
+// after which the whole transaction may succeed when it is run again. These
+// are the PostgreSQL errors 40001 and 40P01, the MySQL error 1213 and the
+// busy errors of SQLite.
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "40001" || pqErr.Code == "40P01"
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY
+	}
+	return false
+}
+
+// withRetry runs fn, and runs it again as the retry policy allows while it
+// fails with a retryable error. It returns the error of the last run.
+func (a *Adapter) withRetry(ctx context.Context, fn func() error) error {
+	policy := a.retry
+	if policy == nil {
+		return fn()
+	}
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+		if backoff > 0 {
+			timer := time.NewTimer(backoff - rand.N(backoff/2+1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
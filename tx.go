@@ -0,0 +1,245 @@
+// Copyright 2021 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entadapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/casbin/casbin/v3/model"
+	"github.com/casbin/casbin/v3/persist"
+	"github.com/casbin/ent-adapter/ent"
+	"github.com/pkg/errors"
+)
+
+// savepoints counts the savepoints created by all adapters, to name them.
+// Several adapters may join the same transaction, so a counter per adapter
+// would repeat names, and MySQL replaces a savepoint of the same name.
+var savepoints atomic.Uint64
+
+// txKey is the context key of the transaction the writes of the adapter join.
+type txKey struct{}
+
+// joinedTx is a transaction carried by a context. It is either a transaction
+// of the caller, given by what it runs statements with, or one begun by the
+// adapter itself.
+type joinedTx struct {
+	eq dialect.ExecQuerier
+	// tx is the transaction if it may belong to the adapter. It is used as is
+	// if eq is nil, or if the adapter began it.
+	tx *ent.Tx
+}
+
+// NewTxContext returns a copy of ctx carrying tx. The writes of the adapter
+// with the returned context, e.g. AddPolicyCtx, join tx instead of committing
+// on their own, so they are committed or rolled back with the other changes
+// of the caller. tx must belong to a client on the database of the adapter.
+func NewTxContext(ctx context.Context, tx *ent.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, joinedTx{eq: tx.Client().Driver(), tx: tx})
+}
+
+// NewSQLTxContext is like NewTxContext for a transaction of a *sql.DB.
+func NewSQLTxContext(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, joinedTx{eq: entsql.Conn{ExecQuerier: tx}})
+}
+
+// joinDriver is a dialect.Driver that runs everything in a transaction owned
+// by the caller. Its transactions neither commit nor roll back.
+type joinDriver struct {
+	dialect.ExecQuerier
+	dialect string
+}
+
+// Dialect implements the dialect.Driver interface.
+func (d *joinDriver) Dialect() string {
+	return d.dialect
+}
+
+// Tx returns a transaction whose Commit and Rollback do nothing.
+func (d *joinDriver) Tx(context.Context) (dialect.Tx, error) {
+	return dialect.NopTx(d), nil
+}
+
+// Close does nothing, the transaction is closed by the caller.
+func (d *joinDriver) Close() error {
+	return nil
+}
+
+// joined returns the transaction carried by ctx, or nil if there is none.
+func (a *Adapter) joined(ctx context.Context) (*ent.Tx, error) {
+	j, ok := ctx.Value(txKey{}).(joinedTx)
+	if !ok {
+		return nil, nil
+	}
+	if j.eq == nil {
+		return j.tx, nil
+	}
+	if _, ok := a.txs.Load(j.tx); ok {
+		return j.tx, nil
+	}
+	drv := &joinDriver{ExecQuerier: j.eq, dialect: a.client.Driver().Dialect()}
+	return ent.NewClient(ent.Driver(a.wrapDriver(drv))).Tx(ctx)
+}
+
+// withTx returns a copy of ctx carrying tx, a transaction begun by the adapter.
+// Until done is called, contexts made by NewTxContext for tx join it as well.
+func (a *Adapter) withTx(ctx context.Context, tx *ent.Tx) (_ context.Context, done func()) {
+	a.txs.Store(tx, struct{}{})
+	return context.WithValue(ctx, txKey{}, joinedTx{tx: tx}), func() { a.txs.Delete(tx) }
+}
+
+// savepoint runs fn in a savepoint of tx. The savepoint is released if fn
+// returns nil and rolled back to otherwise, which undoes the writes of fn
+// but leaves the rest of tx intact.
+func (a *Adapter) savepoint(ctx context.Context, tx *ent.Tx, fn func(ctx context.Context, tx *ent.Tx) error) error {
+	name := fmt.Sprintf("casbin_savepoint_%d", savepoints.Add(1))
+	drv := tx.Client().Driver()
+	if err := drv.Exec(ctx, "SAVEPOINT "+name, []any{}, nil); err != nil {
+		return err
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			_ = drv.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name, []any{}, nil)
+			panic(v)
+		}
+	}()
+	if err := fn(ctx, tx); err != nil {
+		if rerr := drv.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name, []any{}, nil); rerr != nil {
+			err = errors.Wrapf(err, "rolling back to savepoint: %v", rerr)
+		}
+		return err
+	}
+	if err := drv.Exec(ctx, "RELEASE SAVEPOINT "+name, []any{}, nil); err != nil {
+		return err
+	}
+	// The write is not committed yet, but it is on the primary already.
+	a.lastWrite.Store(time.Now().UnixNano())
+	return nil
+}
+
+// BeginTransaction starts a transaction for a casbin TransactionalEnforcer.
+// The enforcer writes the changes it buffered through the adapter of the
+// returned context when it commits, so they are stored all or none.
+// It fails with ErrReadOnly if the adapter is read-only.
+func (a *Adapter) BeginTransaction(ctx context.Context) (persist.TransactionContext, error) {
+	if a.readOnly {
+		return nil, ErrReadOnly
+	}
+	tx, err := a.begin(ctx, a.client)
+	if err != nil {
+		return nil, err
+	}
+	t := &transaction{adapter: a, tx: tx}
+	t.ctx, t.done = a.withTx(ctx, tx)
+	return t, nil
+}
+
+// transaction is the persist.TransactionContext returned by BeginTransaction.
+type transaction struct {
+	adapter *Adapter
+	tx      *ent.Tx
+	// ctx carries tx, so the writes made with it join tx.
+	ctx  context.Context
+	done func()
+}
+
+// Commit commits the transaction.
+func (t *transaction) Commit() error {
+	defer t.done()
+	if err := t.tx.Commit(); err != nil {
+		return err
+	}
+	t.adapter.lastWrite.Store(time.Now().UnixNano())
+	return nil
+}
+
+// Rollback rolls back the transaction.
+func (t *transaction) Rollback() error {
+	defer t.done()
+	return t.tx.Rollback()
+}
+
+// GetAdapter returns an adapter that writes in the transaction.
+func (t *transaction) GetAdapter() persist.Adapter {
+	return &txAdapter{adapter: t.adapter, ctx: t.ctx}
+}
+
+// txAdapter is an adapter whose writes join the transaction carried by ctx.
+// Loads are not part of the transaction.
+type txAdapter struct {
+	adapter *Adapter
+	ctx     context.Context
+}
+
+var (
+	_ persist.TransactionalAdapter = (*Adapter)(nil)
+	_ persist.BatchAdapter         = (*txAdapter)(nil)
+	_ persist.UpdatableAdapter     = (*txAdapter)(nil)
+)
+
+// LoadPolicy loads all policy rules from the storage.
+func (t *txAdapter) LoadPolicy(model model.Model) error {
+	return t.adapter.LoadPolicyCtx(t.ctx, model)
+}
+
+// SavePolicy saves all policy rules to the storage in the transaction.
+func (t *txAdapter) SavePolicy(model model.Model) error {
+	return t.adapter.SavePolicyCtx(t.ctx, model)
+}
+
+// AddPolicy adds a policy rule to the storage in the transaction.
+func (t *txAdapter) AddPolicy(sec string, ptype string, rule []string) error {
+	return t.adapter.AddPolicyCtx(t.ctx, sec, ptype, rule)
+}
+
+// RemovePolicy removes a policy rule from the storage in the transaction.
+func (t *txAdapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	return t.adapter.RemovePolicyCtx(t.ctx, sec, ptype, rule)
+}
+
+// RemoveFilteredPolicy removes policy rules that match the filter from the storage in the transaction.
+func (t *txAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	return t.adapter.RemoveFilteredPolicyCtx(t.ctx, sec, ptype, fieldIndex, fieldValues...)
+}
+
+// AddPolicies adds policy rules to the storage in the transaction.
+func (t *txAdapter) AddPolicies(sec string, ptype string, rules [][]string) error {
+	return t.adapter.AddPoliciesCtx(t.ctx, sec, ptype, rules)
+}
+
+// RemovePolicies removes policy rules from the storage in the transaction.
+func (t *txAdapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	return t.adapter.RemovePoliciesCtx(t.ctx, sec, ptype, rules)
+}
+
+// UpdatePolicy updates a policy rule in the storage in the transaction.
+func (t *txAdapter) UpdatePolicy(sec string, ptype string, oldRule, newRule []string) error {
+	return t.adapter.UpdatePolicyCtx(t.ctx, sec, ptype, oldRule, newRule)
+}
+
+// UpdatePolicies updates policy rules in the storage in the transaction.
+func (t *txAdapter) UpdatePolicies(sec string, ptype string, oldRules, newRules [][]string) error {
+	return t.adapter.UpdatePoliciesCtx(t.ctx, sec, ptype, oldRules, newRules)
+}
+
+// UpdateFilteredPolicies replaces the policy rules that match the filter in the storage in the transaction.
+func (t *txAdapter) UpdateFilteredPolicies(sec string, ptype string, newRules [][]string, fieldIndex int, fieldValues ...string) ([][]string, error) {
+	return t.adapter.UpdateFilteredPoliciesCtx(t.ctx, sec, ptype, newRules, fieldIndex, fieldValues...)
+}